@@ -30,6 +30,19 @@ func (i *IgnoredExpressions) ToSlice() []string {
 	return ignored
 }
 
+type ExcludedPatterns []string
+
+func (e *ExcludedPatterns) String() string {
+
+	return fmt.Sprintln([]string(*e))
+}
+
+func (e *ExcludedPatterns) Set(value string) error {
+
+	*e = append(*e, value)
+	return nil
+}
+
 type CriticalExpressions map[string]telescope.OutdatedScope
 
 func (c *CriticalExpressions) String() string {
@@ -47,8 +60,8 @@ func (c *CriticalExpressions) Set(value string) error {
 
 	desiredScopeStr, expression, found := strings.Cut(value, ":")
 	expressionMap := map[string]telescope.OutdatedScope(*c)
-	if !found {
-		desiredScopeStr, expression = telescope.MAJOR.String(), desiredScopeStr
+	if !found || telescope.IsCriticalQualifier(desiredScopeStr) {
+		desiredScopeStr, expression = telescope.MAJOR.String(), value
 	}
 
 	desiredScope := telescope.OutdatedScopeStrToEnum(desiredScopeStr)
@@ -75,8 +88,27 @@ func (c *CriticalExpressions) ToScopeMap() map[telescope.OutdatedScope][]string
 var (
 	filePath            string
 	outdatedScope       string
+	minReleaseAge       string
+	groups              string
+	condaChannelAlias   string
+	condaChannels       string
+	condaSubdirs        string
+	npmRegistry         string
+	cratesIndex         string
+	rubyGemsHost        string
+	mavenRepository     string
+	packagistRepository string
+	nugetFlatContainer  string
+	ociRegistry         string
+	githubApi           string
+	terraformRegistry   string
+	helmRepositories    string
 	skipUnknown         bool
+	directOnly          bool
+	includePrerelease   bool
+	latestCommit        bool
 	strictSemVer        bool
+	excludedPatterns    ExcludedPatterns
 	ignoredExpressions  IgnoredExpressions  = make(map[string]bool)
 	criticalExpressions CriticalExpressions = make(map[string]telescope.OutdatedScope)
 )
@@ -85,8 +117,27 @@ func init() {
 
 	flag.StringVar(&filePath, "f", "go.mod", "dependencies file path")
 	flag.StringVar(&outdatedScope, "s", "major", "desired outdated scope")
+	flag.StringVar(&minReleaseAge, "min-age", "0s", "minimum release age before a version counts as latest (e.g. 7d, 12h)")
+	flag.StringVar(&groups, "groups", "", "only report dependencies of the given comma-separated groups (e.g. main)")
+	flag.StringVar(&condaChannelAlias, "conda-channel-alias", telescope.CondaChannelAlias, "base url of conda channels given by name")
+	flag.StringVar(&condaChannels, "conda-default-channels", strings.Join(telescope.CondaDefaultChannels, ","), "comma-separated channels the conda defaults channel expands to")
+	flag.StringVar(&condaSubdirs, "conda-subdirs", strings.Join(telescope.CondaSubdirs, ","), "comma-separated platform subdirectories conda packages are searched in")
+	flag.StringVar(&npmRegistry, "npm-registry", telescope.NpmRegistry, "base url of the npm registry")
+	flag.StringVar(&cratesIndex, "crates-index", telescope.CratesIndex, "base url of the crates.io sparse index")
+	flag.StringVar(&rubyGemsHost, "rubygems-host", telescope.RubyGemsHost, "base url of the RubyGems versions api")
+	flag.StringVar(&mavenRepository, "maven-repository", telescope.MavenRepository, "base url of the Maven repository")
+	flag.StringVar(&packagistRepository, "packagist-repository", telescope.PackagistRepository, "base url of the Packagist p2 metadata")
+	flag.StringVar(&nugetFlatContainer, "nuget-flat-container", telescope.NugetFlatContainer, "base url of the NuGet v3 flat container")
+	flag.StringVar(&ociRegistry, "oci-registry", telescope.OciRegistry, "base url of the registry serving Docker Hub images")
+	flag.StringVar(&githubApi, "github-api", telescope.GithubApi, "base url of the GitHub REST api resolving action tags")
+	flag.StringVar(&terraformRegistry, "terraform-registry", telescope.TerraformRegistry, "base url of the registry serving public Terraform providers and modules")
+	flag.StringVar(&helmRepositories, "helm-repository-config", "", "path of the Helm repositories.yaml resolving chart repositories given by name (default $HELM_REPOSITORY_CONFIG or $HOME/.config/helm/repositories.yaml)")
+	flag.BoolVar(&directOnly, "direct-only", false, "skip dependencies which are only required indirectly")
 	flag.BoolVar(&skipUnknown, "skip-unknown", false, "skip dependencies with unknown versions")
+	flag.BoolVar(&includePrerelease, "include-prerelease", false, "allow pre-releases to be reported as the latest version")
+	flag.BoolVar(&latestCommit, "latest-commit", false, "query the latest commit of the default branch of go modules")
 	flag.BoolVar(&strictSemVer, "strict-semver", false, "parse dependencies file with strict SemVer format")
+	flag.Var(&excludedPatterns, "exclude", "skip the paths matching a .gitignore pattern while scanning a directory")
 	flag.Var(&ignoredExpressions, "i", "ignore specific dependencies with regular expression")
 	flag.Var(&criticalExpressions, "c", "highlight critical dependencies with regular expression")
 	flag.Usage = usage
@@ -94,19 +145,80 @@ func init() {
 
 func usage() {
 
-	fmt.Fprintf(os.Stderr, "Usage: telescope [-f file_path] [-s outdated_scope] [-i ignored_dependency] [-c critical_dependency] [--skip-unknown] [--strict-semver]\n")
+	fmt.Fprintf(os.Stderr, "Usage: telescope [-f file_path] [-s outdated_scope] [-i ignored_dependency] [-c critical_dependency] [--direct-only] [--groups groups] [--conda-channel-alias url] [--conda-default-channels channels] [--conda-subdirs subdirs] [--npm-registry url] [--crates-index url] [--rubygems-host url] [--maven-repository url] [--packagist-repository url] [--nuget-flat-container url] [--oci-registry url] [--github-api url] [--terraform-registry url] [--helm-repository-config file_path] [--min-age release_age] [--include-prerelease] [--latest-commit] [--skip-unknown] [--strict-semver]\n")
+	fmt.Fprintf(os.Stderr, "       telescope scan [--exclude pattern] [flags] [directory]\n")
 	flag.PrintDefaults()
 }
 
 func main() {
 
-	flag.Parse()
+	scanning := len(os.Args) > 1 && os.Args[1] == "scan"
+	if scanning {
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
+
+	minAge, err := telescope.ParseReleaseAge(minReleaseAge)
+	if err != nil {
+		panic(err)
+	}
+
+	telescope.CondaChannelAlias = strings.TrimSuffix(condaChannelAlias, "/")
+	telescope.CondaDefaultChannels = strings.Split(condaChannels, ",")
+	telescope.CondaSubdirs = strings.Split(condaSubdirs, ",")
+	telescope.NpmRegistry = strings.TrimSuffix(npmRegistry, "/")
+	telescope.CratesIndex = strings.TrimSuffix(cratesIndex, "/")
+	telescope.RubyGemsHost = strings.TrimSuffix(rubyGemsHost, "/")
+	telescope.MavenRepository = strings.TrimSuffix(mavenRepository, "/")
+	telescope.PackagistRepository = strings.TrimSuffix(packagistRepository, "/")
+	telescope.NugetFlatContainer = strings.TrimSuffix(nugetFlatContainer, "/")
+	telescope.OciRegistry = strings.TrimSuffix(ociRegistry, "/")
+	telescope.GithubApi = strings.TrimSuffix(githubApi, "/")
+	telescope.TerraformRegistry = strings.TrimSuffix(terraformRegistry, "/")
+	telescope.HelmRepositoryConfig = helmRepositories
+
+	var dependencyGroups []string
+	if groups != "" {
+		dependencyGroups = strings.Split(groups, ",")
+	}
+
+	options := telescope.AtlasOptions{
+		StrictSemVer: strictSemVer,
+		DirectOnly:   directOnly,
+		Groups:       dependencyGroups,
+		ReleasePolicy: telescope.ReleasePolicy{
+			MinAge:            minAge,
+			IncludePrerelease: includePrerelease,
+			QueryLatestCommit: latestCommit,
+		},
+		IgnoredExpressions:  ignoredExpressions.ToSlice(),
+		CriticalExpressions: criticalExpressions.ToScopeMap(),
+	}
 
-	atlas := telescope.NewAtlas(filePath, strictSemVer, ignoredExpressions.ToSlice(), criticalExpressions.ToScopeMap())
-	criticalFound := atlas.ReportOutdated(
-		telescope.OutdatedScopeStrToEnum(outdatedScope),
-		skipUnknown,
-	)
+	var criticalFound bool
+	if scanning {
+		root := "."
+		if flag.NArg() > 0 {
+			root = flag.Arg(0)
+		}
+		criticalFound, err = telescope.Scan(
+			root,
+			excludedPatterns,
+			options,
+			telescope.OutdatedScopeStrToEnum(outdatedScope),
+			skipUnknown,
+		)
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		atlas := telescope.NewAtlas(filePath, options)
+		criticalFound = atlas.ReportOutdated(
+			telescope.OutdatedScopeStrToEnum(outdatedScope),
+			skipUnknown,
+		)
+	}
 	if criticalFound {
 		os.Exit(1)
 	}
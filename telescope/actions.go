@@ -0,0 +1,185 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultGithubApi = "https://api.github.com"
+	githubTagsUrl    = "%s/repos/%s/tags?per_page=100"
+)
+
+var GithubApi = defaultGithubApi
+
+var commitShaPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+type WorkflowAction struct {
+	Name    string
+	Ref     string
+	Comment string
+}
+
+type GithubTag struct {
+	Name string `json:"name"`
+}
+
+func (d *Dependency) queryVersionsActions(policy ReleasePolicy) {
+
+	if policy.MinAge > 0 {
+		logrus.Warn(fmt.Sprintf("skip --min-age for action %s: unknown release time", d.Name))
+	}
+	current, ok := d.VersionCurrent.(*DockerTag)
+	if !ok {
+		return
+	}
+
+	tags, ok := queryGithubTags(strings.TrimRight(GithubApi, "/"), actionRepository(d.Name))
+	if !ok {
+		logrus.Debug(fmt.Sprintf("failed to query tags of %s", d.Name))
+		return
+	}
+
+	versions := []IVersion{}
+	for _, tag := range tags {
+		actionTag, err := NewDockerTag(tag)
+		if err != nil || !current.matches(actionTag) {
+			continue
+		}
+		versions = append(versions, actionTag)
+	}
+	d.selectLatestVersions(versions, policy)
+}
+
+func queryGithubTags(api, repository string) ([]string, bool) {
+
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	tags := []string{}
+	tagsUrl := fmt.Sprintf(githubTagsUrl, api, repository)
+	for tagsUrl != "" {
+		response, err := getVersionsResponse(tagsUrl, header)
+		if err != nil {
+			logrus.Debug(err.Error())
+			return nil, false
+		}
+		var githubTags []GithubTag
+		err = json.NewDecoder(response.Body).Decode(&githubTags)
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK || err != nil {
+			return nil, false
+		}
+		for _, tag := range githubTags {
+			tags = append(tags, tag.Name)
+		}
+		tagsUrl = nextPageUrl(response.Header, api)
+	}
+	return tags, true
+}
+
+func actionRepository(name string) string {
+
+	segments := strings.SplitN(name, "/", 3)
+	if len(segments) < 2 {
+		return name
+	}
+	return segments[0] + "/" + segments[1]
+}
+
+func isWorkflowFile(filePath string) bool {
+
+	fileName := filePath[strings.LastIndex(filePath, "/")+1:]
+	if fileName == "action.yml" || fileName == "action.yaml" {
+		return true
+	}
+	return strings.Contains(filePath, ".github/workflows/") &&
+		(strings.HasSuffix(fileName, ".yml") || strings.HasSuffix(fileName, ".yaml"))
+}
+
+func parseWorkflowActions(fileBytes []byte) []WorkflowAction {
+
+	var document yaml.Node
+	err := yaml.Unmarshal(fileBytes, &document)
+	if err != nil {
+		panic(err)
+	}
+
+	actions := []WorkflowAction{}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for idx := 0; idx+1 < len(node.Content); idx += 2 {
+				key, value := node.Content[idx], node.Content[idx+1]
+				if key.Value != "uses" || value.Kind != yaml.ScalarNode {
+					continue
+				}
+				name, ref, _ := strings.Cut(value.Value, "@")
+				comment := value.LineComment
+				if comment == "" {
+					comment = key.LineComment
+				}
+				actions = append(
+					actions,
+					WorkflowAction{Name: name, Ref: ref, Comment: strings.TrimSpace(strings.TrimPrefix(comment, "#"))},
+				)
+			}
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(&document)
+	return actions
+}
+
+func buildAtlasWorkflow(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	atlas := Atlas{
+		name:         "",
+		language:     ACTIONS,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	reported := map[string]bool{}
+	for _, action := range parseWorkflowActions(fileBytes) {
+		key := action.Name + "@" + action.Ref
+		if action.Ref == "" || strings.HasPrefix(action.Name, "docker://") || reported[key] ||
+			matchRegExpPatterns(ignoredPatterns, action.Name) {
+			continue
+		}
+		reported[key] = true
+
+		var dep *Dependency
+		if commitShaPattern.MatchString(action.Ref) {
+			// e.g. "# v4.1.1", "# tag=v4.1.1" or "# pin@v4.1.1"
+			version := ""
+			if fields := strings.Fields(action.Comment); len(fields) > 0 {
+				version = fields[0][strings.LastIndexAny(fields[0], "=@")+1:]
+			}
+			dep = NewActionsDependency(action.Name, version).(*Dependency)
+			if dep.VersionCurrent == nil {
+				dep.VersionCurrentLiteral = action.Ref
+			}
+		} else {
+			dep = NewActionsDependency(action.Name, action.Ref).(*Dependency)
+			dep.Unpinned = dep.VersionCurrent == nil
+		}
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
@@ -0,0 +1,136 @@
+package telescope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRepository(t *testing.T) {
+
+	params := []struct {
+		name     string
+		expected string
+	}{
+		{name: "actions/checkout", expected: "actions/checkout"},
+		{name: "github/codeql-action/init", expected: "github/codeql-action"},
+		{name: "org/workflows/.github/workflows/build.yml", expected: "org/workflows"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, actionRepository(param.name), param.expected)
+			},
+		)
+	}
+}
+
+func TestIsWorkflowFile(t *testing.T) {
+
+	assert.True(t, isWorkflowFile(".github/workflows/pull_request.yml"))
+	assert.True(t, isWorkflowFile("/src/.github/workflows/tag.yaml"))
+	assert.True(t, isWorkflowFile("actions/setup/action.yml"))
+	assert.False(t, isWorkflowFile("environment.yml"))
+}
+
+func TestParseWorkflowActions(t *testing.T) {
+
+	actions := parseWorkflowActions([]byte(`on: push
+jobs:
+  call:
+    uses: org/workflows/.github/workflows/build.yml@v1
+  build:
+    steps:
+      - uses: actions/checkout@8e5e7e5ab8b370d6c329ec480221332ada57f0ab # v3.5.2
+      - uses: ./.github/actions/setup
+      - run: make test
+`))
+
+	assert.Equal(
+		t,
+		actions,
+		[]WorkflowAction{
+			{Name: "org/workflows/.github/workflows/build.yml", Ref: "v1"},
+			{Name: "actions/checkout", Ref: "8e5e7e5ab8b370d6c329ec480221332ada57f0ab", Comment: "v3.5.2"},
+			{Name: "./.github/actions/setup"},
+		},
+	)
+}
+
+func TestQueryVersionsActions(t *testing.T) {
+
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/repos/actions/checkout/tags" && r.URL.Query().Get("page") == "":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/actions/checkout/tags?per_page=100&page=2>; rel="next"`, server.URL))
+				w.Write([]byte(`[{"name": "v4.1.1"}, {"name": "v4.1.0"}, {"name": "v4"}]`))
+			case r.URL.Path == "/repos/actions/checkout/tags":
+				w.Write([]byte(`[{"name": "v3.5.2"}, {"name": "v3"}, {"name": "v2-beta"}]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	api := GithubApi
+	GithubApi = server.URL
+	defer func() { GithubApi = api }()
+
+	major := NewActionsDependency("actions/checkout", "v3").(*Dependency)
+	major.queryVersionsActions(ReleasePolicy{})
+	assert.Equal(t, major.VersionLatest.String(), "v4")
+	assert.Equal(t, major.GetOutdatedScope(), MAJOR)
+
+	exact := NewActionsDependency("actions/checkout", "v4.1.0").(*Dependency)
+	exact.queryVersionsActions(ReleasePolicy{})
+	assert.Equal(t, exact.VersionLatest.String(), "v4.1.1")
+	assert.Equal(t, exact.GetOutdatedScope(), PATCH)
+
+	cooled := NewActionsDependency("actions/checkout", "v3").(*Dependency)
+	cooled.queryVersionsActions(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, cooled.VersionLatest.String(), "v4")
+}
+
+func TestBuildAtlasWorkflow(t *testing.T) {
+
+	atlas := buildAtlasWorkflow(
+		[]byte(`name: Pull Request
+on: pull_request
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/setup-go@93397bea11091df50f3d7e59dc26a7711a8bcfbe # v4.1.0
+      - uses: golangci/golangci-lint-action@master
+      - uses: codecov/codecov-action@4fe8c5f003fae66aa5ebb77cfd3e7bfbbda0b6b0
+      - uses: ./.github/actions/release
+      - uses: docker://alpine:3.18
+  release:
+    steps:
+      - uses: actions/checkout@v3
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, ACTIONS)
+	assert.Equal(t, len(atlas.dependencies), 4)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "v3")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrent.String(), "v4.1.0")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).VersionCurrentLiteral, "4fe8c5f003fae66aa5ebb77cfd3e7bfbbda0b6b0")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetOutdatedScope(), UNKNOWN)
+}
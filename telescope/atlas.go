@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	toml "github.com/pelletier/go-toml/v2"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 type Language int
@@ -19,34 +21,88 @@ type Language int
 const (
 	GO Language = iota
 	PYTHON
+	CONDA
+	JAVASCRIPT
+	RUST
+	RUBY
+	JAVA
+	PHP
+	DOTNET
+	DOCKER
+	ACTIONS
+	TERRAFORM
+	HELM
 )
 
 func (l Language) String() string {
-	return [...]string{"GO", "PYTHON"}[l]
+	return [...]string{"GO", "PYTHON", "CONDA", "JAVASCRIPT", "RUST", "RUBY", "JAVA", "PHP", "DOTNET", "DOCKER", "ACTIONS", "TERRAFORM", "HELM"}[l]
 }
 
 type IReportable interface {
 	ReportOutdated(scope OutdatedScope, skipUnknown bool) bool
 }
 
+type AtlasOptions struct {
+	StrictSemVer        bool
+	DirectOnly          bool
+	Groups              []string
+	ReleasePolicy       ReleasePolicy
+	IgnoredExpressions  []string
+	CriticalExpressions map[OutdatedScope][]string
+}
+
 type Atlas struct {
 	name         string
 	language     Language
-	criticalMap  map[OutdatedScope][]*regexp.Regexp
+	policy       ReleasePolicy
+	criticalMap  map[OutdatedScope][]criticalRule
 	dependencies []IDependable
 	outdatedMap  map[OutdatedScope][]IDependable
+	workspace    bool
+}
+
+var requirementsFilePattern = regexp.MustCompile(`requirements.*\.(?:txt|in)$`)
+
+var criticalQualifiers = []string{"direct", "indirect", "main", "dev"}
+
+type criticalRule struct {
+	qualifier string
+	pattern   *regexp.Regexp
 }
 
 type PoetryLockPackage struct {
-	Name     string `toml:"name"`
-	Version  string `toml:"version"`
-	Category string `toml:"category"`
+	Name         string                 `toml:"name"`
+	Version      string                 `toml:"version"`
+	Category     string                 `toml:"category"`
+	Dependencies map[string]interface{} `toml:"dependencies"`
 }
 
 type PoetryLock struct {
 	Packages []PoetryLockPackage `toml:"package"`
 }
 
+type PyprojectToml struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			Dependencies    map[string]interface{} `toml:"dependencies"`
+			DevDependencies map[string]interface{} `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]interface{} `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+		Pdm struct {
+			Source []struct {
+				Name string `toml:"name"`
+				Url  string `toml:"url"`
+			} `toml:"source"`
+		} `toml:"pdm"`
+	} `toml:"tool"`
+}
+
 type PipfileLockPackage struct {
 	Version string `json:"version"`
 }
@@ -56,12 +112,7 @@ type PipfileLock struct {
 	Develop map[string]PipfileLockPackage `json:"develop"`
 }
 
-func NewAtlas(
-	filePath string,
-	strictSemVer bool,
-	ignoredExpressions []string,
-	criticalExpressions map[OutdatedScope][]string,
-) IReportable {
+func NewAtlas(filePath string, options AtlasOptions) IReportable {
 
 	var atlas IReportable
 
@@ -69,24 +120,79 @@ func NewAtlas(
 	splitPath := strings.Split(filePath, "/")
 	fileName := splitPath[len(splitPath)-1]
 
-	ignoredPatterns := compileRegExpRules(ignoredExpressions)
-	criticalPatterns := make(map[OutdatedScope][]*regexp.Regexp)
-	for scope, exprs := range criticalExpressions {
-		criticalPatterns[scope] = compileRegExpRules(exprs)
+	strictSemVer := options.StrictSemVer
+	ignoredPatterns := compileRegExpRules(options.IgnoredExpressions)
+	criticalPatterns := make(map[OutdatedScope][]criticalRule)
+	for scope, exprs := range options.CriticalExpressions {
+		criticalPatterns[scope] = compileCriticalRules(exprs)
 	}
 
-	switch fileName {
-	case "go.mod":
+	switch {
+	case fileName == "go.mod":
 		atlas = buildAtlasGoMod(fileBytes, strictSemVer, ignoredPatterns, criticalPatterns)
-	case "poetry.lock":
-		atlas = buildAtlasPoetryLock(fileBytes, strictSemVer, ignoredPatterns, criticalPatterns)
-	case "Pipfile.lock":
-		atlas = buildAtlasPipfileLock(fileBytes, strictSemVer, ignoredPatterns, criticalPatterns)
+	case fileName == "go.work":
+		atlas = buildAtlasGoWork(filePath, fileBytes, strictSemVer, ignoredPatterns, criticalPatterns)
+	case fileName == "poetry.lock":
+		atlas = buildAtlasPoetryLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Pipfile.lock":
+		atlas = buildAtlasPipfileLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "uv.lock":
+		atlas = buildAtlasUvLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "pdm.lock":
+		atlas = buildAtlasPdmLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "environment.yml" || fileName == "environment.yaml":
+		atlas = buildAtlasCondaEnvironment(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "conda-lock.yml" || fileName == "conda-lock.yaml":
+		atlas = buildAtlasCondaLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "package-lock.json" || fileName == "npm-shrinkwrap.json":
+		atlas = buildAtlasPackageLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "yarn.lock":
+		atlas = buildAtlasYarnLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "pnpm-lock.yaml":
+		atlas = buildAtlasPnpmLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Cargo.lock":
+		atlas = buildAtlasCargoLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Gemfile.lock" || fileName == "gems.locked":
+		atlas = buildAtlasGemfileLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "pom.xml":
+		atlas = buildAtlasPomXml(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case strings.HasSuffix(fileName, ".versions.toml"):
+		atlas = buildAtlasGradleCatalog(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "composer.lock":
+		atlas = buildAtlasComposerLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "packages.lock.json":
+		atlas = buildAtlasNugetLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Directory.Packages.props":
+		atlas = buildAtlasDirectoryPackagesProps(fileBytes, ignoredPatterns, criticalPatterns)
+	case isWorkflowFile(filePath):
+		atlas = buildAtlasWorkflow(fileBytes, ignoredPatterns, criticalPatterns)
+	case isDockerfile(fileName):
+		atlas = buildAtlasDockerfile(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == ".terraform.lock.hcl":
+		atlas = buildAtlasTerraformLock(filePath, fileBytes, ignoredPatterns, criticalPatterns)
+	case strings.HasSuffix(fileName, ".tf"):
+		atlas = buildAtlasTerraformConfiguration(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Chart.yaml":
+		atlas = buildAtlasChartYaml(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "Chart.lock":
+		atlas = buildAtlasChartLock(fileBytes, ignoredPatterns, criticalPatterns)
+	case fileName == "pyproject.toml":
+		atlas = buildAtlasPyprojectToml(fileBytes, ignoredPatterns, criticalPatterns)
+	case requirementsFilePattern.MatchString(fileName):
+		atlas = buildAtlasRequirementsTxt(filePath, ignoredPatterns, criticalPatterns)
 	default:
 		panic(fmt.Errorf("unknown dep file: %s", filePath))
 	}
 
+	atlas.(*Atlas).policy = options.ReleasePolicy
+	if options.DirectOnly {
+		atlas.(*Atlas).dropIndirectDependencies()
+	}
+	if len(options.Groups) > 0 {
+		atlas.(*Atlas).dropOtherGroups(options.Groups)
+	}
 	atlas.(*Atlas).sortLexicographically()
+	atlas.(*Atlas).sortDevelopmentLast()
 	atlas.(*Atlas).queryVersionsInformation()
 	atlas.(*Atlas).buildOutdatedMap()
 	return atlas
@@ -117,6 +223,32 @@ func compileRegExpRules(regExpStrings []string) []*regexp.Regexp {
 	return patterns
 }
 
+func compileCriticalRules(expressions []string) []criticalRule {
+
+	rules := []criticalRule{}
+	for _, expression := range expressions {
+		var qualifier string
+		if head, tail, found := strings.Cut(expression, ":"); found && IsCriticalQualifier(head) {
+			qualifier, expression = head, tail
+		}
+		rules = append(
+			rules,
+			criticalRule{qualifier: qualifier, pattern: compileRegExpRules([]string{expression})[0]},
+		)
+	}
+	return rules
+}
+
+func IsCriticalQualifier(qualifier string) bool {
+
+	for _, known := range criticalQualifiers {
+		if qualifier == known {
+			return true
+		}
+	}
+	return false
+}
+
 func matchRegExpPatterns(patterns []*regexp.Regexp, payload string) bool {
 
 	for _, pattern := range patterns {
@@ -131,13 +263,23 @@ func buildAtlasGoMod(
 	fileBytes []byte,
 	strictSemVer bool,
 	ignoredPatterns []*regexp.Regexp,
-	criticalPatterns map[OutdatedScope][]*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
 ) IReportable {
 
 	modObject, err := modfile.Parse("go.mod", fileBytes, nil)
 	if err != nil {
-		logrus.Fatal(err.Error())
+		panic(err)
 	}
+	return buildAtlasGoModFile(modObject, modObject.Replace, strictSemVer, ignoredPatterns, criticalPatterns)
+}
+
+func buildAtlasGoModFile(
+	modObject *modfile.File,
+	replaces []*modfile.Replace,
+	strictSemVer bool,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) *Atlas {
 
 	atlas := Atlas{
 		name:         modObject.Module.Mod.Path,
@@ -145,22 +287,148 @@ func buildAtlasGoMod(
 		criticalMap:  criticalPatterns,
 		dependencies: []IDependable{},
 	}
+	excludedVersions := map[string][]string{}
+	for _, exclude := range modObject.Exclude {
+		excludedVersions[exclude.Mod.Path] = append(excludedVersions[exclude.Mod.Path], exclude.Mod.Version)
+	}
 	for _, require := range modObject.Require {
 		if matchRegExpPatterns(ignoredPatterns, require.Mod.Path) {
 			continue
 		}
-		atlas.appendDependency(
-			NewDependency(require.Mod.Path, require.Mod.Version, strictSemVer),
+
+		replace := findReplacement(replaces, require.Mod)
+		switch {
+		case replace == nil:
+			dep := NewGoDependency(require.Mod.Path, require.Mod.Version, strictSemVer, require.Indirect)
+			dep.(*Dependency).ExcludedVersions = excludedVersions[require.Mod.Path]
+			atlas.appendDependency(dep)
+		case replace.New.Version == "":
+			atlas.appendDependency(
+				&Dependency{
+					Name:                  require.Mod.Path,
+					VersionCurrentLiteral: require.Mod.Version,
+					Indirect:              require.Indirect,
+					LocalPath:             replace.New.Path,
+				},
+			)
+		default:
+			dep := NewGoDependency(require.Mod.Path, replace.New.Version, strictSemVer, require.Indirect)
+			dep.(*Dependency).ReplacementPath = replace.New.Path
+			dep.(*Dependency).ExcludedVersions = excludedVersions[replace.New.Path]
+			atlas.appendDependency(dep)
+		}
+	}
+	return &atlas
+}
+
+func buildAtlasGoWork(
+	filePath string,
+	fileBytes []byte,
+	strictSemVer bool,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	workObject, err := modfile.ParseWork("go.work", fileBytes, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	modObjects := []*modfile.File{}
+	workspaceModules := map[string]string{}
+	for _, use := range workObject.Use {
+		memberPath := filepath.Join(use.Path, "go.mod")
+		if !filepath.IsAbs(use.Path) {
+			memberPath = filepath.Join(filepath.Dir(filePath), memberPath)
+		}
+		memberBytes, err := os.ReadFile(memberPath)
+		if err != nil {
+			panic(err)
+		}
+		modObject, err := modfile.Parse(memberPath, memberBytes, nil)
+		if err != nil {
+			panic(err)
+		}
+		modObjects = append(modObjects, modObject)
+		workspaceModules[modObject.Module.Mod.Path] = use.Path
+	}
+
+	atlas := Atlas{
+		name:         filePath,
+		language:     GO,
+		criticalMap:  criticalPatterns,
+		dependencies: []IDependable{},
+		workspace:    true,
+	}
+	mergedDependencies := map[string]*Dependency{}
+	for _, modObject := range modObjects {
+		member := buildAtlasGoModFile(
+			modObject,
+			mergeWorkspaceReplacements(workObject.Replace, modObject.Replace),
+			strictSemVer,
+			ignoredPatterns,
+			criticalPatterns,
 		)
+		for _, dep := range member.dependencies {
+			memberDep := dep.(*Dependency)
+			if usePath, ok := workspaceModules[memberDep.Name]; ok && memberDep.ReplacementPath == "" {
+				memberDep.LocalPath, memberDep.VersionCurrent = usePath, nil
+			}
+
+			key := strings.Join(
+				[]string{memberDep.Name, memberDep.VersionCurrentLiteral, memberDep.ReplacementPath, memberDep.LocalPath},
+				" ",
+			)
+			if mergedDep, ok := mergedDependencies[key]; ok {
+				mergedDep.UsedBy = append(mergedDep.UsedBy, member.name)
+				mergedDep.Indirect = mergedDep.Indirect && memberDep.Indirect
+				continue
+			}
+			memberDep.UsedBy = []string{member.name}
+			mergedDependencies[key] = memberDep
+			atlas.appendDependency(memberDep)
+		}
 	}
 	return &atlas
 }
 
+func mergeWorkspaceReplacements(workReplaces, modReplaces []*modfile.Replace) []*modfile.Replace {
+
+	replaces := append([]*modfile.Replace{}, workReplaces...)
+	for _, modReplace := range modReplaces {
+		overridden := false
+		for _, workReplace := range workReplaces {
+			overridden = overridden || workReplace.Old.Path == modReplace.Old.Path
+		}
+		if !overridden {
+			replaces = append(replaces, modReplace)
+		}
+	}
+	return replaces
+}
+
+func findReplacement(replaces []*modfile.Replace, mod module.Version) *modfile.Replace {
+
+	var wildcard *modfile.Replace
+	for _, replace := range replaces {
+		if replace.Old.Path != mod.Path {
+			continue
+		}
+		if replace.Old.Version == mod.Version {
+			return replace
+		}
+		if replace.Old.Version == "" {
+			wildcard = replace
+		}
+	}
+	return wildcard
+}
+
 func buildAtlasPoetryLock(
+	filePath string,
 	fileBytes []byte,
-	strictSemVer bool,
 	ignoredPatterns []*regexp.Regexp,
-	criticalPatterns map[OutdatedScope][]*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
 ) IReportable {
 
 	var poetryLock PoetryLock
@@ -176,22 +444,93 @@ func buildAtlasPoetryLock(
 		criticalMap:  criticalPatterns,
 		outdatedMap:  map[OutdatedScope][]IDependable{},
 	}
+	groups := resolvePoetryGroups(
+		poetryLock.Packages,
+		readPyprojectToml(filepath.Join(filepath.Dir(filePath), "pyproject.toml")),
+	)
 	for _, pkg := range poetryLock.Packages {
 		if matchRegExpPatterns(ignoredPatterns, pkg.Name) {
 			continue
 		}
-		atlas.appendDependency(
-			NewDependency(pkg.Name, pkg.Version, strictSemVer),
-		)
+		dep := NewPythonDependency(pkg.Name, pkg.Version)
+		dep.(*Dependency).Groups = groups[normalizePythonName(pkg.Name)]
+		if pkg.Category != "" {
+			dep.(*Dependency).Groups = []string{pkg.Category}
+		}
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
+
+func readPyprojectToml(filePath string) *PyprojectToml {
+
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("no pyproject.toml found: %s", err.Error()))
+		return nil
+	}
+
+	var pyproject PyprojectToml
+	err = toml.Unmarshal(fileBytes, &pyproject)
+	if err != nil {
+		panic(err)
+	}
+	return &pyproject
+}
+
+func buildAtlasPyprojectToml(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var pyproject PyprojectToml
+	err := toml.Unmarshal(fileBytes, &pyproject)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     PYTHON,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	declaredDependencies := map[string]*Dependency{}
+	for _, requirement := range pyproject.declaredRequirements() {
+		if matchRegExpPatterns(ignoredPatterns, requirement.Name) {
+			continue
+		}
+		key := strings.Join([]string{normalizePythonName(requirement.Name), requirement.Specifier, requirement.Location}, " ")
+		if dep, ok := declaredDependencies[key]; ok {
+			if !dep.InGroups([]string{requirement.group}) {
+				dep.Groups = append(dep.Groups, requirement.group)
+			}
+			continue
+		}
+
+		var dep IDependable
+		if requirement.Location != "" {
+			dep = &Dependency{
+				Name:                  requirement.Name,
+				VersionCurrentLiteral: requirement.Specifier,
+				LocalPath:             requirement.Location,
+			}
+		} else {
+			dep = NewPythonConstraintDependency(requirement.Name, requirement.Specifier, requirement.poetry)
+		}
+		dep.(*Dependency).Groups = []string{requirement.group}
+		declaredDependencies[key] = dep.(*Dependency)
+		atlas.appendDependency(dep)
 	}
 	return &atlas
 }
 
 func buildAtlasPipfileLock(
 	fileBytes []byte,
-	strictSemVer bool,
 	ignoredPatterns []*regexp.Regexp,
-	criticalPatterns map[OutdatedScope][]*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
 ) IReportable {
 
 	var pipfileLock PipfileLock
@@ -207,24 +546,131 @@ func buildAtlasPipfileLock(
 		criticalMap:  criticalPatterns,
 		outdatedMap:  map[OutdatedScope][]IDependable{},
 	}
-	for _, pkgGroup := range []map[string]PipfileLockPackage{pipfileLock.Default, pipfileLock.Develop} {
-		for name, pkg := range pkgGroup {
+	pkgGroups := []struct {
+		name     string
+		packages map[string]PipfileLockPackage
+	}{
+		{name: mainGroup, packages: pipfileLock.Default},
+		{name: devGroup, packages: pipfileLock.Develop},
+	}
+	groupedDependencies := map[string]*Dependency{}
+	for _, pkgGroup := range pkgGroups {
+		for name, pkg := range pkgGroup.packages {
 			if matchRegExpPatterns(ignoredPatterns, name) {
 				continue
 			}
-			atlas.appendDependency(
-				NewDependency(name, pkg.Version[2:], strictSemVer),
-			)
+			if dep, ok := groupedDependencies[name]; ok {
+				dep.Groups = append(dep.Groups, pkgGroup.name)
+				continue
+			}
+			dep := NewPythonDependency(name, pkg.Version[2:])
+			dep.(*Dependency).Groups = []string{pkgGroup.name}
+			groupedDependencies[name] = dep.(*Dependency)
+			atlas.appendDependency(dep)
 		}
 	}
 	return &atlas
 }
 
+func buildAtlasRequirementsTxt(
+	filePath string,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	requirements, constraints, index := parseRequirementsFile(filePath, map[string]bool{})
+
+	atlas := Atlas{
+		name:         filePath,
+		language:     PYTHON,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	atlas.appendPythonRequirements(requirements, constraints, index, ignoredPatterns)
+	return &atlas
+}
+
+func (a *Atlas) appendPythonRequirements(
+	requirements []PythonRequirement,
+	constraints map[string]string,
+	index string,
+	ignoredPatterns []*regexp.Regexp,
+) {
+
+	listed := map[PythonRequirement]bool{}
+	for _, requirement := range requirements {
+		if matchRegExpPatterns(ignoredPatterns, requirement.Name) || listed[requirement] {
+			continue
+		}
+		listed[requirement] = true
+
+		version := requirement.Version
+		if version == "" {
+			version = constraints[normalizePythonName(requirement.Name)]
+		}
+		switch {
+		case requirement.Location != "":
+			a.appendDependency(
+				&Dependency{
+					Name:                  requirement.Name,
+					VersionCurrentLiteral: requirement.Specifier,
+					LocalPath:             requirement.Location,
+				},
+			)
+		case version == "":
+			a.appendDependency(
+				&Dependency{
+					Name:                  requirement.Name,
+					VersionCurrentLiteral: requirement.Specifier,
+					Unpinned:              true,
+				},
+			)
+		default:
+			dep := NewPythonDependency(requirement.Name, version)
+			dep.(*Dependency).PackageIndex = pythonPackageIndex(index)
+			a.appendDependency(dep)
+		}
+	}
+}
+
+func containsString(items []string, item string) bool {
+
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Atlas) appendDependency(dep IDependable) {
 
 	a.dependencies = append(a.dependencies, dep)
 }
 
+func (a *Atlas) dropIndirectDependencies() {
+
+	directDependencies := []IDependable{}
+	for _, dep := range a.dependencies {
+		if !dep.(*Dependency).Indirect {
+			directDependencies = append(directDependencies, dep)
+		}
+	}
+	a.dependencies = directDependencies
+}
+
+func (a *Atlas) dropOtherGroups(groups []string) {
+
+	groupedDependencies := []IDependable{}
+	for _, dep := range a.dependencies {
+		if len(dep.(*Dependency).Groups) == 0 || dep.(*Dependency).InGroups(groups) {
+			groupedDependencies = append(groupedDependencies, dep)
+		}
+	}
+	a.dependencies = groupedDependencies
+}
+
 func (a *Atlas) sortLexicographically() {
 
 	sort.SliceStable(
@@ -238,13 +684,24 @@ func (a *Atlas) sortLexicographically() {
 	)
 }
 
+func (a *Atlas) sortDevelopmentLast() {
+
+	sort.SliceStable(
+		a.dependencies,
+		func(i, j int) bool {
+			return !a.dependencies[i].(*Dependency).IsDevelopment() &&
+				a.dependencies[j].(*Dependency).IsDevelopment()
+		},
+	)
+}
+
 func (a *Atlas) queryVersionsInformation() {
 
 	queryWaitGroup := new(sync.WaitGroup)
 
 	queryWaitGroup.Add(len(a.dependencies))
 	for _, dep := range a.dependencies {
-		go dep.QueryReleaseVersions(a.language, queryWaitGroup)
+		go dep.QueryReleaseVersions(a.language, a.policy, queryWaitGroup)
 	}
 	queryWaitGroup.Wait()
 }
@@ -252,17 +709,17 @@ func (a *Atlas) queryVersionsInformation() {
 func (a *Atlas) buildOutdatedMap() {
 
 	outdatedMap := map[OutdatedScope][]IDependable{
-		MAJOR:      {},
-		MINOR:      {},
-		PATCH:      {},
-		UP_TO_DATE: {},
-		UNKNOWN:    {},
+		MAJOR:               {},
+		MINOR:               {},
+		PATCH:               {},
+		UP_TO_DATE:          {},
+		UNTAGGED:            {},
+		LOCAL:               {},
+		UNPINNED:            {},
+		CONSTRAINT_OUTDATED: {},
+		UNKNOWN:             {},
 	}
 	for _, dep := range a.dependencies {
-		if dep.(*Dependency).VersionCurrent == nil {
-			outdatedMap[UNKNOWN] = append(outdatedMap[UNKNOWN], dep)
-			continue
-		}
 		depOutdatedScope := dep.(*Dependency).GetOutdatedScope()
 		outdatedMap[depOutdatedScope] = append(outdatedMap[depOutdatedScope], dep)
 	}
@@ -280,8 +737,16 @@ func (a *Atlas) ReportOutdated(desiredScope OutdatedScope, skipUnknown bool) boo
 		color := MapScopeColor[scp]
 		criticalFound = a.reportByScope(scp, color) || criticalFound
 	}
+	criticalFound = a.reportUntaggedDependencies() || criticalFound
+	criticalFound = a.reportConstraintOutdatedDependencies() || criticalFound
+	if a.workspace {
+		a.reportSharedDependencies()
+	}
+	a.reportWarnings()
+	a.reportDependenciesByStatus(LOCAL)
+	a.reportDependenciesByStatus(UNPINNED)
 	if !skipUnknown {
-		a.reportUnknownDependencies()
+		a.reportDependenciesByStatus(UNKNOWN)
 	}
 
 	return criticalFound
@@ -289,19 +754,67 @@ func (a *Atlas) ReportOutdated(desiredScope OutdatedScope, skipUnknown bool) boo
 
 func buildReportItem(dep IDependable) string {
 
+	if dep.(*Dependency).LocalPath != "" {
+		return fmt.Sprintf("%-50s => %s%s", dep.(*Dependency).Name, dep.(*Dependency).LocalPath, buildUsedByNote(dep))
+	}
+	indirectNote := ""
+	if dep.(*Dependency).Indirect {
+		indirectNote = " (indirect)"
+	}
 	if dep.(*Dependency).VersionCurrent == nil || dep.(*Dependency).VersionLatest == nil {
 		return fmt.Sprintf(
-			"%-50s %-20s",
+			"%-50s %-20s%s",
 			dep.(*Dependency).Name,
 			dep.(*Dependency).VersionCurrentLiteral,
+			indirectNote,
 		)
 	}
-	return fmt.Sprintf(
+	item := fmt.Sprintf(
 		"%-50s %-20s %-20s",
 		dep.(*Dependency).Name,
 		dep.(*Dependency).VersionCurrent,
 		dep.(*Dependency).VersionLatest,
-	)
+	) + indirectNote
+	item += buildGroupNote(dep)
+	if dep.(*Dependency).ReplacementPath != "" {
+		item += fmt.Sprintf(" (replaced by %s)", dep.(*Dependency).ReplacementPath)
+	}
+	if dep.(*Dependency).PackageIndex != "" {
+		item += fmt.Sprintf(" (index %s)", dep.(*Dependency).PackageIndex)
+	}
+	item += buildAppVersionNote(dep)
+	if dep.(*Dependency).LatestModulePath != "" {
+		item += fmt.Sprintf(" (module %s)", dep.(*Dependency).LatestModulePath)
+	}
+	if dep.(*Dependency).VersionLatestPrerelease != nil {
+		item += fmt.Sprintf(" (pre-release %s)", dep.(*Dependency).VersionLatestPrerelease)
+	}
+	return item + buildUsedByNote(dep)
+}
+
+func buildGroupNote(dep IDependable) string {
+
+	if !dep.(*Dependency).IsDevelopment() {
+		return ""
+	}
+	return fmt.Sprintf(" (group %s)", strings.Join(dep.(*Dependency).Groups, ", "))
+}
+
+func buildAppVersionNote(dep IDependable) string {
+
+	current, latest := dep.(*Dependency).AppVersion, dep.(*Dependency).AppVersionLatest
+	if current == "" || latest == "" || current == latest {
+		return ""
+	}
+	return fmt.Sprintf(" (app %s => %s)", current, latest)
+}
+
+func buildUsedByNote(dep IDependable) string {
+
+	if len(dep.(*Dependency).UsedBy) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (used by %s)", strings.Join(dep.(*Dependency).UsedBy, ", "))
 }
 
 func (a *Atlas) reportByScope(scope OutdatedScope, color int) bool {
@@ -320,19 +833,122 @@ func (a *Atlas) reportByScope(scope OutdatedScope, color int) bool {
 	var criticalFound bool = false
 	for _, dep := range a.outdatedMap[scope] {
 
-		var patternHit bool = false
-		for scp, patterns := range a.criticalMap {
-			if scp != scope {
+		if a.isCritical(scope, dep) {
+			criticalFound = criticalFound || true
+			fmt.Printf("* %s\n", buildReportItem(dep))
+		} else {
+			fmt.Printf("  %s\n", buildReportItem(dep))
+		}
+	}
+	fmt.Print("\n\033[0m")
+
+	return criticalFound
+}
+
+func (a *Atlas) isCritical(scope OutdatedScope, dep IDependable) bool {
+
+	var patternHit bool = false
+	for scp, rules := range a.criticalMap {
+		if scp != scope {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.qualifier != "" && !dep.(*Dependency).MatchQualifier(rule.qualifier) {
 				continue
 			}
-			patternHit = patternHit || matchRegExpPatterns(patterns, dep.(*Dependency).Name)
+			patternHit = patternHit || matchRegExpPatterns([]*regexp.Regexp{rule.pattern}, dep.(*Dependency).Name)
 		}
+	}
+	return patternHit
+}
 
-		if patternHit {
-			criticalFound = criticalFound || true
-			fmt.Printf("* %s\n", buildReportItem(dep))
+func buildUntaggedReportItem(dep IDependable) string {
+
+	latestTag := "-"
+	if dep.(*Dependency).VersionLatest != nil {
+		latestTag = dep.(*Dependency).VersionLatest.String()
+	}
+	base, committed := describePseudoVersion(dep.(*Dependency).VersionCurrentLiteral)
+	if base == "" {
+		base = "none"
+	}
+	item := fmt.Sprintf(
+		"%-50s %-20s %-20s (%s against latest tag, base %s, committed %s",
+		dep.(*Dependency).Name,
+		dep.(*Dependency).VersionCurrent,
+		latestTag,
+		dep.(*Dependency).GetTaggedScope(),
+		base,
+		committed.Format("2006-01-02"),
+	)
+	if dep.(*Dependency).VersionLatestCommit != nil {
+		item += fmt.Sprintf(", latest commit %s", dep.(*Dependency).VersionLatestCommit)
+	}
+	return item + ")"
+}
+
+func (a *Atlas) reportUntaggedDependencies() bool {
+
+	if len(a.outdatedMap[UNTAGGED]) == 0 {
+		return false
+	}
+	fmt.Printf(
+		"\033[%dm\n[ %d UNTAGGED dependencies ]%s\n\n",
+		MapScopeColor[UNTAGGED],
+		len(a.outdatedMap[UNTAGGED]),
+		strings.Repeat("=", 40),
+	)
+
+	var criticalFound bool = false
+	for _, dep := range a.outdatedMap[UNTAGGED] {
+		if a.isCritical(UNTAGGED, dep) {
+			criticalFound = true
+			fmt.Printf("* %s\n", buildUntaggedReportItem(dep))
 		} else {
-			fmt.Printf("  %s\n", buildReportItem(dep))
+			fmt.Printf("  %s\n", buildUntaggedReportItem(dep))
+		}
+	}
+	fmt.Print("\n\033[0m")
+
+	return criticalFound
+}
+
+func buildConstraintReportItem(dep IDependable) string {
+
+	admitted := "no release"
+	if dep.(*Dependency).VersionConstraintLatest != nil {
+		admitted = fmt.Sprintf("up to %s", dep.(*Dependency).VersionConstraintLatest)
+	}
+	return fmt.Sprintf(
+		"%-50s %-20s %-20s (admits %s)%s%s",
+		dep.(*Dependency).Name,
+		dep.(*Dependency).Constraint,
+		dep.(*Dependency).VersionLatest,
+		admitted,
+		buildGroupNote(dep),
+		buildAppVersionNote(dep),
+	)
+}
+
+func (a *Atlas) reportConstraintOutdatedDependencies() bool {
+
+	if len(a.outdatedMap[CONSTRAINT_OUTDATED]) == 0 {
+		return false
+	}
+	fmt.Printf(
+		"\033[%dm\n[ %d CONSTRAINT_OUTDATED dependencies ]%s\n\n",
+		MapScopeColor[CONSTRAINT_OUTDATED],
+		len(a.outdatedMap[CONSTRAINT_OUTDATED]),
+		strings.Repeat("=", 40),
+	)
+
+	var criticalFound bool = false
+	for _, dep := range a.outdatedMap[CONSTRAINT_OUTDATED] {
+		if a.isCritical(CONSTRAINT_OUTDATED, dep) {
+			criticalFound = true
+			fmt.Printf("* %s\n", buildConstraintReportItem(dep))
+		} else {
+			fmt.Printf("  %s\n", buildConstraintReportItem(dep))
 		}
 	}
 	fmt.Print("\n\033[0m")
@@ -340,17 +956,80 @@ func (a *Atlas) reportByScope(scope OutdatedScope, color int) bool {
 	return criticalFound
 }
 
-func (a *Atlas) reportUnknownDependencies() {
+func (a *Atlas) reportSharedDependencies() {
+
+	versionsByName := map[string][]IDependable{}
+	names := []string{}
+	for _, dep := range a.dependencies {
+		name := dep.(*Dependency).Name
+		if len(versionsByName[name]) == 0 {
+			names = append(names, name)
+		}
+		versionsByName[name] = append(versionsByName[name], dep)
+	}
+
+	sharedNames := []string{}
+	for _, name := range names {
+		if len(versionsByName[name]) > 1 {
+			sharedNames = append(sharedNames, name)
+		}
+	}
+	if len(sharedNames) == 0 {
+		return
+	}
+	fmt.Printf(
+		"\n[ %d SHARED dependencies ]%s\n\n",
+		len(sharedNames),
+		strings.Repeat("=", 40),
+	)
+	for _, name := range sharedNames {
+		fmt.Printf("  %s\n", name)
+		for _, dep := range versionsByName[name] {
+			fmt.Printf(
+				"      %-20s %s\n",
+				dep.(*Dependency).VersionCurrentLiteral,
+				strings.Join(dep.(*Dependency).UsedBy, ", "),
+			)
+		}
+	}
+}
+
+func (a *Atlas) reportWarnings() {
+
+	warnedDependencies := []IDependable{}
+	for _, dep := range a.dependencies {
+		if len(dep.(*Dependency).GetWarnings()) > 0 {
+			warnedDependencies = append(warnedDependencies, dep)
+		}
+	}
+	if len(warnedDependencies) == 0 {
+		return
+	}
+	fmt.Printf(
+		"\n[ %d WARNED dependencies ]%s\n\n",
+		len(warnedDependencies),
+		strings.Repeat("=", 40),
+	)
+	for _, dep := range warnedDependencies {
+		fmt.Printf("  %-50s %-20s\n", dep.(*Dependency).Name, dep.(*Dependency).VersionCurrentLiteral)
+		for _, warning := range dep.(*Dependency).GetWarnings() {
+			fmt.Printf("      ! %s\n", warning)
+		}
+	}
+}
+
+func (a *Atlas) reportDependenciesByStatus(status OutdatedScope) {
 
-	if len(a.outdatedMap[UNKNOWN]) == 0 {
+	if len(a.outdatedMap[status]) == 0 {
 		return
 	}
 	fmt.Printf(
-		"\n[ %d UNKNOWN dependencies ]%s\n\n",
-		len(a.outdatedMap[UNKNOWN]),
+		"\n[ %d %s dependencies ]%s\n\n",
+		len(a.outdatedMap[status]),
+		status.String(),
 		strings.Repeat("=", 40),
 	)
-	for _, dep := range a.outdatedMap[UNKNOWN] {
+	for _, dep := range a.outdatedMap[status] {
 		fmt.Printf("  %s\n", buildReportItem(dep))
 	}
 }
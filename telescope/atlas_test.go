@@ -1,12 +1,28 @@
 package telescope
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
+func writeFiles(t *testing.T, files map[string]string) string {
+
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return root
+}
+
 type SuiteAtlas struct {
 	suite.Suite
 	atlas *Atlas
@@ -24,6 +40,7 @@ func TestCompileRegExpRules(t *testing.T) {
 		{name: "multiple expressions", expressions: []string{"^github.com/.*$", "^k8s.io/.*$"}, expected: 2},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -48,6 +65,7 @@ func TestMatchRegExpPatterns(t *testing.T) {
 		{name: "hit", payload: "k8s.io/api", expected: true},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -60,9 +78,310 @@ func TestMatchRegExpPatterns(t *testing.T) {
 	}
 }
 
+func TestCompileCriticalRules(t *testing.T) {
+
+	rules := compileCriticalRules([]string{"^github.com/.*$", "indirect:^k8s.io/.*$", "(?:golang):.*"})
+	assert.Equal(t, len(rules), 3)
+	assert.Equal(t, rules[0].qualifier, "")
+	assert.Equal(t, rules[1].qualifier, "indirect")
+	assert.Equal(t, rules[1].pattern.String(), "^k8s.io/.*$")
+	assert.Equal(t, rules[2].qualifier, "")
+	assert.Equal(t, rules[2].pattern.String(), "(?:golang):.*")
+}
+
+func TestIsCritical(t *testing.T) {
+
+	atlas := Atlas{
+		criticalMap: map[OutdatedScope][]criticalRule{
+			MAJOR: compileCriticalRules([]string{"direct:^github.com/.*$"}),
+			MINOR: compileCriticalRules([]string{"indirect:^github.com/.*$"}),
+		},
+	}
+	direct := NewGoDependency("github.com/foo/bar", "v1.0.0", false, false)
+	indirect := NewGoDependency("github.com/foo/baz", "v1.0.0", false, true)
+
+	assert.True(t, atlas.isCritical(MAJOR, direct))
+	assert.False(t, atlas.isCritical(MAJOR, indirect))
+	assert.False(t, atlas.isCritical(MINOR, direct))
+	assert.True(t, atlas.isCritical(MINOR, indirect))
+	assert.False(t, atlas.isCritical(PATCH, direct))
+}
+
+func TestBuildAtlasGoModIndirect(t *testing.T) {
+
+	atlas := buildAtlasGoMod(
+		[]byte(`module example.com/mod
+
+go 1.19
+
+require (
+	github.com/foo/bar v1.0.0
+	github.com/foo/baz v1.0.0 // indirect
+)
+`),
+		false,
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+	assert.False(t, atlas.dependencies[0].(*Dependency).Indirect)
+	assert.True(t, atlas.dependencies[1].(*Dependency).Indirect)
+
+	atlas.dropIndirectDependencies()
+	assert.Equal(t, len(atlas.dependencies), 1)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).Name, "github.com/foo/bar")
+}
+
+func TestBuildReportItemIndirect(t *testing.T) {
+
+	unknown := NewGoDependency("github.com/foo/baz", "v1.0.0", false, true)
+	assert.Equal(t, buildReportItem(unknown), fmt.Sprintf("%-50s %-20s (indirect)", "github.com/foo/baz", "v1.0.0"))
+
+	outdated := NewGoDependency("github.com/foo/baz", "v1.0.0", false, true)
+	outdated.(*Dependency).VersionLatest, _ = NewSematicVersion("v1.1.0", false)
+	assert.Equal(
+		t,
+		buildReportItem(outdated),
+		fmt.Sprintf("%-50s %-20s %-20s (indirect)", "github.com/foo/baz", "1.0.0", "1.1.0"),
+	)
+}
+
+func TestBuildAtlasGoModReplace(t *testing.T) {
+
+	atlas := buildAtlasGoMod(
+		[]byte(`module example.com/mod
+
+go 1.19
+
+require (
+	github.com/foo/bar v1.0.0
+	github.com/foo/baz v1.0.0
+	github.com/foo/qux v1.2.0
+)
+
+replace github.com/foo/bar => github.com/fork/bar v1.1.0
+
+replace github.com/foo/baz v1.0.0 => ../baz
+
+replace github.com/foo/baz v0.9.0 => github.com/fork/baz v0.9.1
+
+exclude github.com/foo/qux v1.3.0
+`),
+		false,
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	fork := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, fork.Name, "github.com/foo/bar")
+	assert.Equal(t, fork.ReplacementPath, "github.com/fork/bar")
+	assert.Equal(t, fork.VersionCurrent.String(), "1.1.0")
+
+	local := atlas.dependencies[1].(*Dependency)
+	assert.Equal(t, local.LocalPath, "../baz")
+	assert.Nil(t, local.VersionCurrent)
+	assert.Equal(t, local.GetOutdatedScope(), LOCAL)
+
+	excluded := atlas.dependencies[2].(*Dependency)
+	assert.Equal(t, excluded.ReplacementPath, "")
+	assert.Equal(t, excluded.ExcludedVersions, []string{"v1.3.0"})
+}
+
+func TestBuildAtlasGoWork(t *testing.T) {
+
+	workspace := writeFiles(t, map[string]string{
+		"a/go.mod": "module example.com/a\n\ngo 1.19\n\nrequire (\n\tgithub.com/foo/bar v1.0.0\n\tgithub.com/foo/qux v0.1.0\n)\n\nreplace github.com/foo/qux => ../qux\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.19\n\nrequire (\n\texample.com/a v0.0.0\n\tgithub.com/foo/bar v1.0.0 // indirect\n\tgithub.com/foo/baz v1.2.0\n)\n",
+	})
+
+	workPath := filepath.Join(workspace, "go.work")
+	work := "go 1.19\n\nuse (\n\t./a\n\t" + filepath.Join(workspace, "b") +
+		"\n)\n\nreplace github.com/foo/qux => github.com/fork/qux v1.0.0\n"
+	assert.Nil(t, os.WriteFile(workPath, []byte(work), 0o644))
+	atlas := buildAtlasGoWork(
+		workPath,
+		parseDependenciesFile(workPath),
+		false,
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+	atlas.sortLexicographically()
+
+	dependencies := map[string]*Dependency{}
+	for _, dep := range atlas.dependencies {
+		dependencies[dep.(*Dependency).Name] = dep.(*Dependency)
+	}
+	assert.Equal(t, len(atlas.dependencies), 4)
+	assert.Equal(t, dependencies["github.com/foo/bar"].UsedBy, []string{"example.com/a", "example.com/b"})
+	assert.False(t, dependencies["github.com/foo/bar"].Indirect)
+	assert.Equal(t, dependencies["github.com/foo/baz"].UsedBy, []string{"example.com/b"})
+	assert.Equal(t, dependencies["github.com/foo/qux"].ReplacementPath, "github.com/fork/qux")
+	assert.Equal(t, dependencies["example.com/a"].LocalPath, "./a")
+	assert.Equal(t, dependencies["example.com/a"].GetOutdatedScope(), LOCAL)
+	assert.True(t, atlas.workspace)
+}
+
+func TestBuildAtlasPoetryLockGroups(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"pyproject.toml": `[tool.poetry.dependencies]
+python = "^3.8"
+requests = "^2.28"
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^7.0"
+
+[tool.poetry.group.docs.dependencies]
+Sphinx = "^5.0"
+`,
+		"poetry.lock": `[[package]]
+name = "requests"
+version = "2.28.1"
+
+[package.dependencies]
+urllib3 = ">=1.21.1,<1.27"
+
+[[package]]
+name = "pytest"
+version = "7.2.0"
+
+[package.dependencies]
+urllib3 = "*"
+
+[[package]]
+name = "sphinx"
+version = "5.3.0"
+
+[[package]]
+name = "urllib3"
+version = "1.26.12"
+`,
+	})
+
+	lockPath := filepath.Join(project, "poetry.lock")
+	atlas := buildAtlasPoetryLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	groups := map[string][]string{}
+	for _, dep := range atlas.dependencies {
+		groups[dep.(*Dependency).Name] = dep.(*Dependency).Groups
+	}
+	assert.Equal(t, groups["requests"], []string{"main"})
+	assert.Equal(t, groups["pytest"], []string{"dev"})
+	assert.Equal(t, groups["sphinx"], []string{"docs"})
+	assert.Equal(t, groups["urllib3"], []string{"main", "dev"})
+
+	atlas.dropOtherGroups([]string{"dev", "docs"})
+	atlas.sortLexicographically()
+	atlas.sortDevelopmentLast()
+	names := []string{}
+	for _, dep := range atlas.dependencies {
+		names = append(names, dep.(*Dependency).Name)
+	}
+	assert.Equal(t, names, []string{"urllib3", "pytest", "sphinx"})
+}
+
+func TestBuildAtlasPipfileLockGroups(t *testing.T) {
+
+	atlas := buildAtlasPipfileLock(
+		[]byte(`{
+	"default": {"requests": {"version": "==2.28.1"}, "urllib3": {"version": "==1.26.12"}},
+	"develop": {"pytest": {"version": "==7.2.0"}, "urllib3": {"version": "==1.26.12"}}
+}`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+	atlas.sortLexicographically()
+
+	assert.Equal(t, len(atlas.dependencies), 3)
+	pytest := atlas.dependencies[0].(*Dependency)
+	requests := atlas.dependencies[1].(*Dependency)
+	urllib3 := atlas.dependencies[2].(*Dependency)
+	assert.Equal(t, pytest.Groups, []string{"dev"})
+	assert.True(t, pytest.IsDevelopment())
+	assert.True(t, pytest.MatchQualifier("dev"))
+	assert.Equal(t, requests.Groups, []string{"main"})
+	assert.True(t, requests.MatchQualifier("main"))
+	assert.Equal(t, urllib3.Groups, []string{"main", "dev"})
+	assert.False(t, urllib3.IsDevelopment())
+}
+
+func TestBuildAtlasRequirementsTxt(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"requirements-dev.txt": "-c constraints.txt\nDjango\nFlask>=2.0\nrequests==2.28.1\nrequests==2.28.1\n-e ./plugins/foo\n",
+		"constraints.txt":      "django==4.1.3\n",
+	})
+
+	atlas := buildAtlasRequirementsTxt(
+		filepath.Join(project, "requirements-dev.txt"),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, len(atlas.dependencies), 4)
+	django := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, django.VersionCurrent.String(), "4.1.3")
+	flask := atlas.dependencies[1].(*Dependency)
+	assert.True(t, flask.Unpinned)
+	assert.Equal(t, flask.VersionCurrentLiteral, ">=2.0")
+	assert.Equal(t, flask.GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).VersionCurrent.String(), "2.28.1")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetOutdatedScope(), LOCAL)
+}
+
+func TestBuildAtlasPyprojectToml(t *testing.T) {
+
+	atlas := buildAtlasPyprojectToml(
+		[]byte(`[project]
+dependencies = ["requests (<2.30)", "click>=8.0; python_version >= '3.8'"]
+
+[project.optional-dependencies]
+test = ["pytest[testing]~=7.0", "requests<2.30"]
+
+[tool.poetry.dependencies]
+python = "^3.8"
+httpx = "^0.23"
+mylib = { path = "../mylib", develop = true }
+
+[tool.poetry.group.docs.dependencies]
+sphinx = [
+    { version = "^5.0", python = ">=3.8" },
+    { version = "^4.0", python = "<3.8" },
+]
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+	atlas.sortLexicographically()
+
+	dependencies := map[string]*Dependency{}
+	for _, dep := range atlas.dependencies {
+		dependencies[dep.(*Dependency).Name] = dep.(*Dependency)
+	}
+	assert.Equal(t, len(atlas.dependencies), 6)
+	assert.Equal(t, dependencies["requests"].Constraint.String(), "<2.30")
+	assert.Equal(t, dependencies["requests"].Groups, []string{"main", "test"})
+	assert.Equal(t, dependencies["click"].Constraint.String(), ">=8.0")
+	assert.Equal(t, dependencies["pytest"].Groups, []string{"test"})
+	assert.Equal(t, dependencies["httpx"].Constraint.String(), "^0.23")
+	assert.Equal(t, dependencies["mylib"].GetOutdatedScope(), LOCAL)
+	assert.Equal(t, dependencies["sphinx"].Constraint.String(), "^5.0 || ^4.0")
+
+	latest, _ := NewPep440Version("2.31.0")
+	dependencies["requests"].VersionLatest = latest
+	assert.Equal(t, dependencies["requests"].GetOutdatedScope(), CONSTRAINT_OUTDATED)
+	dependencies["click"].VersionLatest, _ = NewPep440Version("8.1.3")
+	assert.Equal(t, dependencies["click"].GetOutdatedScope(), UP_TO_DATE)
+	assert.Equal(t, dependencies["httpx"].GetOutdatedScope(), UNKNOWN)
+}
+
 func (suite *SuiteAtlas) SetupTest() {
 
-	atlas, _ := NewAtlas("../go.mod", false, []string{}, map[OutdatedScope][]string{}).(*Atlas)
+	atlas, _ := NewAtlas("../go.mod", AtlasOptions{}).(*Atlas)
 	suite.atlas = atlas
 }
 
@@ -0,0 +1,565 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultCondaChannelAlias = "https://conda.anaconda.org"
+	condaRepodataUrl         = "%s/%s/repodata.json"
+)
+
+var CondaChannelAlias = defaultCondaChannelAlias
+
+var CondaDefaultChannels = []string{
+	"https://repo.anaconda.com/pkgs/main",
+	"https://repo.anaconda.com/pkgs/r",
+}
+
+var CondaSubdirs = []string{"linux-64", "noarch"}
+
+var (
+	condaVersionPattern    = regexp.MustCompile(`^[0-9a-z_.+!]+$`)
+	condaSubcomponentRegex = regexp.MustCompile(`[0-9]+|[^0-9]+`)
+	condaMatchSpecPattern  = regexp.MustCompile(`^(?:([^:\s]+)::)?([A-Za-z0-9_.-]+)\s*(.*)$`)
+	condaPrereleaseWords   = []string{"dev", "alpha", "beta", "rc"}
+)
+
+type CondaVersion struct {
+	original   string
+	epoch      int64
+	components [][]interface{}
+	local      [][]interface{}
+}
+
+type CondaEnvironment struct {
+	Name         string        `yaml:"name"`
+	Channels     []string      `yaml:"channels"`
+	Dependencies []interface{} `yaml:"dependencies"`
+}
+
+type CondaLockPackage struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	Manager  string `yaml:"manager"`
+	Platform string `yaml:"platform"`
+	Url      string `yaml:"url"`
+	Category string `yaml:"category"`
+}
+
+type CondaLock struct {
+	Metadata struct {
+		Channels []struct {
+			Url string `yaml:"url"`
+		} `yaml:"channels"`
+	} `yaml:"metadata"`
+	Packages []CondaLockPackage `yaml:"package"`
+}
+
+func NewCondaVersion(version string) (*CondaVersion, error) {
+
+	normalized := strings.ToLower(strings.TrimSpace(version))
+	if !condaVersionPattern.MatchString(normalized) {
+		return nil, fmt.Errorf("invalid conda version string %s", version)
+	}
+
+	condaVersion := &CondaVersion{original: version}
+	if epoch, rest, found := strings.Cut(normalized, "!"); found {
+		value, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid conda version epoch %s", version)
+		}
+		condaVersion.epoch, normalized = value, rest
+	}
+	public, local, _ := strings.Cut(normalized, "+")
+
+	var err error
+	if condaVersion.components, err = splitCondaComponents(public); err != nil {
+		return nil, fmt.Errorf("invalid conda version string %s", version)
+	}
+	if local != "" {
+		if condaVersion.local, err = splitCondaComponents(local); err != nil {
+			return nil, fmt.Errorf("invalid conda version string %s", version)
+		}
+	}
+	return condaVersion, nil
+}
+
+func splitCondaComponents(version string) ([][]interface{}, error) {
+
+	components := [][]interface{}{}
+	for _, component := range strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '_' }) {
+		subcomponents := []interface{}{}
+		for _, subcomponent := range condaSubcomponentRegex.FindAllString(component, -1) {
+			number, err := strconv.ParseInt(subcomponent, 10, 64)
+			switch {
+			case err == nil:
+				subcomponents = append(subcomponents, number)
+			case subcomponent[0] >= '0' && subcomponent[0] <= '9':
+				return nil, err
+			default:
+				if len(subcomponents) == 0 {
+					// a component starting with a letter sorts before the number zero
+					subcomponents = append(subcomponents, int64(0))
+				}
+				subcomponents = append(subcomponents, subcomponent)
+			}
+		}
+		components = append(components, subcomponents)
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("empty conda version")
+	}
+	return components, nil
+}
+
+func (v *CondaVersion) Original() string {
+	return v.original
+}
+
+func (v *CondaVersion) String() string {
+	return strings.ToLower(v.original)
+}
+
+func (v *CondaVersion) leadingNumber(idx int) int64 {
+
+	if idx < len(v.components) {
+		if number, ok := v.components[idx][0].(int64); ok {
+			return number
+		}
+	}
+	return 0
+}
+
+func (v *CondaVersion) Major() int64 {
+	return v.leadingNumber(0)
+}
+
+func (v *CondaVersion) Minor() int64 {
+	return v.leadingNumber(1)
+}
+
+func (v *CondaVersion) Patch() int64 {
+	return v.leadingNumber(2)
+}
+
+func (v *CondaVersion) Prerelease() string {
+
+	for _, component := range v.components {
+		for _, subcomponent := range component {
+			word, ok := subcomponent.(string)
+			if !ok {
+				continue
+			}
+			for _, prerelease := range condaPrereleaseWords {
+				if word == prerelease {
+					return word
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (v *CondaVersion) Compare(other IVersion) int {
+
+	o := other.(*CondaVersion)
+	if result := compareInt64(v.epoch, o.epoch); result != 0 {
+		return result
+	}
+	if result := compareCondaComponents(v.components, o.components); result != 0 {
+		return result
+	}
+	return compareCondaComponents(v.local, o.local)
+}
+
+func (v *CondaVersion) OutdatedScope(other IVersion) OutdatedScope {
+
+	latest := other.(*CondaVersion)
+	if v.Compare(latest) >= 0 {
+		return UP_TO_DATE
+	}
+	if latest.epoch != v.epoch {
+		return MAJOR
+	}
+	for idx := 0; idx < len(v.components) || idx < len(latest.components); idx++ {
+		if compareCondaComponents(v.componentAt(idx), latest.componentAt(idx)) == 0 {
+			continue
+		}
+		switch idx {
+		case 0:
+			return MAJOR
+		case 1:
+			return MINOR
+		default:
+			return PATCH
+		}
+	}
+	return PATCH
+}
+
+func (v *CondaVersion) componentAt(idx int) [][]interface{} {
+
+	if idx < len(v.components) {
+		return [][]interface{}{v.components[idx]}
+	}
+	return [][]interface{}{}
+}
+
+func compareCondaComponents(a, b [][]interface{}) int {
+
+	for idx := 0; idx < len(a) || idx < len(b); idx++ {
+		var componentA, componentB []interface{}
+		if idx < len(a) {
+			componentA = a[idx]
+		}
+		if idx < len(b) {
+			componentB = b[idx]
+		}
+		for sub := 0; sub < len(componentA) || sub < len(componentB); sub++ {
+			var subA, subB interface{} = int64(0), int64(0)
+			if sub < len(componentA) {
+				subA = componentA[sub]
+			}
+			if sub < len(componentB) {
+				subB = componentB[sub]
+			}
+			if result := compareCondaSubcomponents(subA, subB); result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+func compareCondaSubcomponents(a, b interface{}) int {
+
+	rank := func(subcomponent interface{}) int64 {
+		switch subcomponent {
+		case "dev":
+			return 0
+		case "post":
+			return 3
+		}
+		if _, ok := subcomponent.(int64); ok {
+			return 2
+		}
+		return 1
+	}
+	if result := compareInt64(rank(a), rank(b)); result != 0 {
+		return result
+	}
+	switch a := a.(type) {
+	case int64:
+		return compareInt64(a, b.(int64))
+	case string:
+		return strings.Compare(a, b.(string))
+	default:
+		return 0
+	}
+}
+
+type condaMatchSpec struct {
+	channel   string
+	name      string
+	specifier string
+	version   string
+}
+
+func parseCondaMatchSpec(spec string) condaMatchSpec {
+
+	match := condaMatchSpecPattern.FindStringSubmatch(strings.TrimSpace(spec))
+	if match == nil {
+		return condaMatchSpec{name: spec}
+	}
+	matchSpec := condaMatchSpec{channel: match[1], name: match[2], specifier: strings.TrimSpace(match[3])}
+
+	// a single "=" is fuzzy, =1.21 standing for 1.21.*, unless a build follows
+	var version string
+	switch specifier := matchSpec.specifier; {
+	case strings.HasPrefix(specifier, "=="):
+		version = specifier[2:]
+	case strings.HasPrefix(specifier, "=") && strings.Contains(specifier[1:], "="):
+		version = specifier[1:]
+	case specifier != "" && specifier[0] >= '0' && specifier[0] <= '9':
+		version = specifier
+	default:
+		return matchSpec
+	}
+	// the build string follows the version after "=" or a space
+	fields := strings.FieldsFunc(version, func(r rune) bool { return r == '=' || r == ' ' })
+	if len(fields) > 0 && !strings.ContainsAny(fields[0], "*,|<>!~[") {
+		matchSpec.version = fields[0]
+	}
+	return matchSpec
+}
+
+func resolveCondaChannels(channels []string) []string {
+
+	if len(channels) == 0 {
+		channels = []string{"defaults"}
+	}
+	urls := []string{}
+	for _, channel := range channels {
+		switch {
+		case channel == "defaults":
+			urls = append(urls, CondaDefaultChannels...)
+		case strings.Contains(channel, "://"):
+			urls = append(urls, strings.TrimRight(channel, "/"))
+		default:
+			urls = append(urls, fmt.Sprintf("%s/%s", strings.TrimRight(CondaChannelAlias, "/"), channel))
+		}
+	}
+	return urls
+}
+
+func splitCondaPackageUrl(packageUrl string) (string, string) {
+
+	parts := strings.Split(packageUrl, "/")
+	if len(parts) < 3 {
+		return "", ""
+	}
+	channel := strings.Join(parts[:len(parts)-2], "/")
+	if strings.HasPrefix(channel, defaultCondaChannelAlias+"/") {
+		channel = strings.TrimRight(CondaChannelAlias, "/") + strings.TrimPrefix(channel, defaultCondaChannelAlias)
+	}
+	return channel, parts[len(parts)-2]
+}
+
+type CondaRepodataRecord struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type CondaRepodata struct {
+	Packages      map[string]CondaRepodataRecord `json:"packages"`
+	PackagesConda map[string]CondaRepodataRecord `json:"packages.conda"`
+}
+
+type condaRepodataEntry struct {
+	once     sync.Once
+	releases map[string]map[string]time.Time
+}
+
+var condaRepodata sync.Map
+
+func queryCondaRepodata(channel, subdir string) map[string]map[string]time.Time {
+
+	url := fmt.Sprintf(condaRepodataUrl, channel, subdir)
+	cached, _ := condaRepodata.LoadOrStore(url, &condaRepodataEntry{})
+	entry := cached.(*condaRepodataEntry)
+	entry.once.Do(func() {
+		entry.releases = map[string]map[string]time.Time{}
+		response, err := getVersionsResponse(url, nil)
+		if err != nil {
+			logrus.Debug(err.Error())
+			return
+		}
+		defer response.Body.Close()
+
+		var repodata CondaRepodata
+		if response.StatusCode != http.StatusOK || json.NewDecoder(response.Body).Decode(&repodata) != nil {
+			logrus.Debug(fmt.Sprintf("failed to query repodata %s", url))
+			return
+		}
+		for _, records := range []map[string]CondaRepodataRecord{repodata.Packages, repodata.PackagesConda} {
+			for _, record := range records {
+				if entry.releases[record.Name] == nil {
+					entry.releases[record.Name] = map[string]time.Time{}
+				}
+				var built time.Time
+				if record.Timestamp > 0 {
+					built = time.UnixMilli(record.Timestamp)
+				}
+				if published, ok := entry.releases[record.Name][record.Version]; !ok || isEarlierBuild(built, published) {
+					entry.releases[record.Name][record.Version] = built
+				}
+			}
+		}
+	})
+	return entry.releases
+}
+
+func isEarlierBuild(built, published time.Time) bool {
+
+	return !built.IsZero() && (published.IsZero() || built.Before(published))
+}
+
+func (d *Dependency) queryVersionsConda(policy ReleasePolicy) {
+
+	releaseTimes := map[string]time.Time{}
+	for _, channel := range d.Channels {
+		for _, subdir := range d.Subdirs {
+			for ver, built := range queryCondaRepodata(channel, subdir)[d.Name] {
+				if published, ok := releaseTimes[ver]; !ok || isEarlierBuild(built, published) {
+					releaseTimes[ver] = built
+				}
+			}
+		}
+		if len(releaseTimes) > 0 {
+			break
+		}
+	}
+
+	versions := []IVersion{}
+	for ver := range releaseTimes {
+		condaVersion, err := NewCondaVersion(ver)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", ver))
+			continue
+		}
+		versions = append(versions, condaVersion)
+	}
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func buildAtlasCondaEnvironment(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var environment CondaEnvironment
+	err := yaml.Unmarshal(fileBytes, &environment)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         environment.Name,
+		language:     CONDA,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	channels := resolveCondaChannels(environment.Channels)
+	pipLines := []string{}
+	for _, item := range environment.Dependencies {
+		switch value := item.(type) {
+		case string:
+			matchSpec := parseCondaMatchSpec(value)
+			if matchRegExpPatterns(ignoredPatterns, matchSpec.name) {
+				continue
+			}
+			if matchSpec.version == "" {
+				atlas.appendDependency(
+					&Dependency{
+						Name:                  matchSpec.name,
+						VersionCurrentLiteral: matchSpec.specifier,
+						Unpinned:              true,
+					},
+				)
+				continue
+			}
+			specChannels := channels
+			if matchSpec.channel != "" {
+				specChannels = resolveCondaChannels([]string{matchSpec.channel})
+			}
+			atlas.appendDependency(NewCondaDependency(matchSpec.name, matchSpec.version, specChannels, CondaSubdirs))
+		case map[string]interface{}:
+			pipSection, _ := value["pip"].([]interface{})
+			for _, line := range pipSection {
+				if line, ok := line.(string); ok {
+					pipLines = append(pipLines, line)
+				}
+			}
+		}
+	}
+
+	requirements, constraints, index := parseRequirementsContent(
+		strings.Join(pipLines, "\n"),
+		filepath.Dir(filePath),
+		map[string]bool{},
+	)
+	atlas.appendPythonRequirements(requirements, constraints, index, ignoredPatterns)
+	return &atlas
+}
+
+func buildAtlasCondaLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var condaLock CondaLock
+	err := yaml.Unmarshal(fileBytes, &condaLock)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     CONDA,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	lockedChannels := []string{}
+	for _, channel := range condaLock.Metadata.Channels {
+		lockedChannels = append(lockedChannels, channel.Url)
+	}
+	lockedDependencies := map[string]*Dependency{}
+	for _, pkg := range condaLock.Packages {
+		if matchRegExpPatterns(ignoredPatterns, pkg.Name) {
+			continue
+		}
+
+		group := pkg.Category
+		if group == "" {
+			group = mainGroup
+		}
+		channels, subdirs := resolveCondaChannels(lockedChannels), []string{pkg.Platform}
+		if channel, subdir := splitCondaPackageUrl(pkg.Url); channel != "" {
+			channels, subdirs = []string{channel}, []string{subdir}
+		}
+		if subdirs[0] != "noarch" {
+			subdirs = append(subdirs, "noarch")
+		}
+
+		key := strings.Join([]string{pkg.Manager, pkg.Name, pkg.Version}, " ")
+		if dep, ok := lockedDependencies[key]; ok {
+			for _, subdir := range subdirs {
+				if !containsString(dep.Subdirs, subdir) && pkg.Manager != "pip" {
+					dep.Subdirs = append(dep.Subdirs, subdir)
+				}
+			}
+			if !dep.InGroups([]string{group}) {
+				dep.Groups = append(dep.Groups, group)
+			}
+			continue
+		}
+
+		var dep IDependable
+		if pkg.Manager == "pip" {
+			dep = NewPythonDependency(pkg.Name, pkg.Version)
+		} else {
+			dep = NewCondaDependency(pkg.Name, pkg.Version, channels, subdirs)
+		}
+		dep.(*Dependency).Groups = []string{group}
+		lockedDependencies[key] = dep.(*Dependency)
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
@@ -0,0 +1,236 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondaVersionCompare(t *testing.T) {
+
+	ordered := []string{
+		"0.4", "0.4.1.rc", "0.4.1", "0.4.1.post1", "0.5a1", "0.5b3", "0.5c1", "0.5",
+		"1.0dev1", "1.0a1", "1.0rc1", "1.0", "1.0post1", "1.1.1r", "1.1.1s", "1.1.2", "1!0.1",
+	}
+	for idx := 0; idx < len(ordered)-1; idx++ {
+		lower, err := NewCondaVersion(ordered[idx])
+		assert.Nil(t, err)
+		upper, err := NewCondaVersion(ordered[idx+1])
+		assert.Nil(t, err)
+		assert.Equal(t, lower.Compare(upper), -1, "%s < %s", ordered[idx], ordered[idx+1])
+	}
+
+	padded, _ := NewCondaVersion("1.0.0")
+	short, _ := NewCondaVersion("1.0")
+	assert.Equal(t, padded.Compare(short), 0)
+
+	for _, invalid := range []string{"", "1.0-1", "1.0 beta", "x!1.0"} {
+		_, err := NewCondaVersion(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestCondaVersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		name     string
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{name: "up to date", current: "1.21.5", latest: "1.21.5", expected: UP_TO_DATE},
+		{name: "major", current: "1.21.5", latest: "2.0.0", expected: MAJOR},
+		{name: "minor", current: "1.21.5", latest: "1.24.0", expected: MINOR},
+		{name: "patch", current: "1.21.5", latest: "1.21.6", expected: PATCH},
+		{name: "letter", current: "1.1.1r", latest: "1.1.1s", expected: PATCH},
+		{name: "calver", current: "2022.10.1", latest: "2023.1.1", expected: MAJOR},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewCondaVersion(param.current)
+				latest, _ := NewCondaVersion(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestParseCondaMatchSpec(t *testing.T) {
+
+	params := []struct {
+		spec     string
+		expected condaMatchSpec
+	}{
+		{spec: "numpy=1.21", expected: condaMatchSpec{name: "numpy", specifier: "=1.21"}},
+		{spec: "numpy==1.21.5", expected: condaMatchSpec{name: "numpy", specifier: "==1.21.5", version: "1.21.5"}},
+		{spec: "numpy 1.21.5 py39h_0", expected: condaMatchSpec{name: "numpy", specifier: "1.21.5 py39h_0", version: "1.21.5"}},
+		{
+			spec:     "conda-forge::scipy=1.7.3=py39hc65b3f8_0",
+			expected: condaMatchSpec{channel: "conda-forge", name: "scipy", specifier: "=1.7.3=py39hc65b3f8_0", version: "1.7.3"},
+		},
+		{spec: "pandas>=1.3", expected: condaMatchSpec{name: "pandas", specifier: ">=1.3"}},
+		{spec: "pandas=1.3.*", expected: condaMatchSpec{name: "pandas", specifier: "=1.3.*"}},
+		{spec: "pip", expected: condaMatchSpec{name: "pip"}},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.spec,
+			func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, parseCondaMatchSpec(param.spec), param.expected)
+			},
+		)
+	}
+}
+
+func TestQueryVersionsConda(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/conda-forge/linux-64/repodata.json":
+				w.Write([]byte(`{
+	"packages": {
+		"numpy-1.21.5-py39h_0.tar.bz2": {"name": "numpy", "version": "1.21.5", "timestamp": 1640995200000},
+		"numpy-1.24.0rc1-py39h_0.tar.bz2": {"name": "numpy", "version": "1.24.0rc1", "timestamp": 1669852800000}
+	},
+	"packages.conda": {
+		"numpy-1.23.5-py39h_0.conda": {"name": "numpy", "version": "1.23.5", "timestamp": 1667260800000}
+	}
+}`))
+			case "/conda-forge/noarch/repodata.json":
+				w.Write([]byte(`{"packages": {"tqdm-4.64.1-pyhd8ed1ab_0.tar.bz2": {"name": "tqdm", "version": "4.64.1"}}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	alias := CondaChannelAlias
+	CondaChannelAlias = server.URL
+	defer func() { CondaChannelAlias = alias }()
+
+	channels := resolveCondaChannels([]string{"bioconda", "conda-forge"})
+	assert.Equal(t, channels, []string{server.URL + "/bioconda", server.URL + "/conda-forge"})
+
+	numpy := NewCondaDependency("numpy", "1.21.5", channels, CondaSubdirs).(*Dependency)
+	numpy.queryVersionsConda(ReleasePolicy{})
+	assert.Equal(t, numpy.VersionLatest.String(), "1.23.5")
+	assert.Equal(t, numpy.VersionLatestPrerelease.String(), "1.24.0rc1")
+	assert.Equal(t, numpy.GetOutdatedScope(), MINOR)
+
+	tqdm := NewCondaDependency("tqdm", "4.64.1", channels, CondaSubdirs).(*Dependency)
+	tqdm.queryVersionsConda(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Nil(t, tqdm.VersionLatest)
+
+	channel, subdir := splitCondaPackageUrl("https://conda.anaconda.org/conda-forge/linux-64/numpy-1.21.5-py39h_0.conda")
+	assert.Equal(t, channel, server.URL+"/conda-forge")
+	assert.Equal(t, subdir, "linux-64")
+}
+
+func TestBuildAtlasCondaEnvironment(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"environment.yml": `name: analysis
+channels:
+  - conda-forge
+  - defaults
+dependencies:
+  - python==3.9.15
+  - bioconda::samtools==1.16.1
+  - pandas>=1.3
+  - pip
+  - pip:
+    - requests==2.28.1
+    - -r requirements.txt
+`,
+		"requirements.txt": "tqdm\n",
+	})
+
+	envPath := filepath.Join(project, "environment.yml")
+	atlas := buildAtlasCondaEnvironment(
+		envPath,
+		parseDependenciesFile(envPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, CONDA)
+	assert.Equal(t, len(atlas.dependencies), 6)
+	python := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, python.VersionCurrent.String(), "3.9.15")
+	assert.Equal(t, python.Channels[0], CondaChannelAlias+"/conda-forge")
+	assert.Equal(t, python.Channels[1:], CondaDefaultChannels)
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).Channels, []string{CondaChannelAlias + "/bioconda"})
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).Name, "pip")
+	requests := atlas.dependencies[4].(*Dependency)
+	assert.Equal(t, requests.Channels, []string(nil))
+	assert.Equal(t, requests.VersionCurrent.(*Pep440Version).String(), "2.28.1")
+	assert.True(t, atlas.dependencies[5].(*Dependency).Unpinned)
+}
+
+func TestBuildAtlasCondaLock(t *testing.T) {
+
+	atlas := buildAtlasCondaLock(
+		[]byte(`version: 1
+metadata:
+  channels:
+  - url: conda-forge
+  platforms:
+  - linux-64
+  - osx-arm64
+package:
+- name: numpy
+  version: 1.21.5
+  manager: conda
+  platform: linux-64
+  url: https://conda.anaconda.org/conda-forge/linux-64/numpy-1.21.5-py39h_0.conda
+  category: main
+- name: numpy
+  version: 1.21.5
+  manager: conda
+  platform: osx-arm64
+  url: https://conda.anaconda.org/conda-forge/osx-arm64/numpy-1.21.5-py39h_0.conda
+  category: main
+- name: pytest
+  version: 7.2.0
+  manager: conda
+  platform: linux-64
+  category: dev
+- name: requests
+  version: 2.28.1
+  manager: pip
+  platform: linux-64
+  url: https://files.pythonhosted.org/packages/requests-2.28.1-py3-none-any.whl
+  category: main
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, len(atlas.dependencies), 3)
+	numpy := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, numpy.Channels, []string{CondaChannelAlias + "/conda-forge"})
+	assert.Equal(t, numpy.Subdirs, []string{"linux-64", "noarch", "osx-arm64"})
+	pytest := atlas.dependencies[1].(*Dependency)
+	assert.Equal(t, pytest.Channels, []string{CondaChannelAlias + "/conda-forge"})
+	assert.Equal(t, pytest.Subdirs, []string{"linux-64", "noarch"})
+	assert.True(t, pytest.IsDevelopment())
+	requests := atlas.dependencies[2].(*Dependency)
+	assert.Equal(t, len(requests.Channels), 0)
+	assert.Equal(t, requests.VersionCurrent.String(), "2.28.1")
+}
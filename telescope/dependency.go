@@ -1,41 +1,67 @@
 package telescope
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"sort"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/mod/module"
 )
 
 const (
-	proxyUrlGoModule      = "https://proxy.golang.org/%s/@v/list"
-	proxyUrlPythonPackage = "https://pypi.org/pypi/%s/json"
+	proxyUrlGoModule     = "https://proxy.golang.org/%s/@v/list"
+	proxyUrlGoModuleInfo = "https://proxy.golang.org/%s/@v/%s.info"
 )
 
 type IDependable interface {
-	QueryReleaseVersions(language Language, wg *sync.WaitGroup)
+	QueryReleaseVersions(language Language, policy ReleasePolicy, wg *sync.WaitGroup)
 	GetOutdatedScope() OutdatedScope
 }
 
 type Dependency struct {
-	Name                  string
-	StrictSemVer          bool
-	VersionCurrentLiteral string
-	VersionCurrent        *semver.Version
-	VersionLatest         *semver.Version
+	Name                    string
+	StrictSemVer            bool
+	VersionCurrentLiteral   string
+	VersionCurrent          IVersion
+	VersionLatest           IVersion
+	VersionLatestPrerelease IVersion
+	VersionLatestCommit     IVersion
+	Indirect                bool
+	ReplacementPath         string
+	LocalPath               string
+	Constraint              IConstraint
+	VersionConstraintLatest IVersion
+	Channels                []string
+	Subdirs                 []string
+	PackageIndex            string
+	AppVersion              string
+	AppVersionLatest        string
+	Unpinned                bool
+	Groups                  []string
+	ExcludedVersions        []string
+	UsedBy                  []string
+	LatestModulePath        string
+	Retracted               bool
+	RetractedRationale      string
+	Deprecated              string
+	Yanked                  bool
+	YankedReason            string
 }
 
-type PypiJson struct {
-	Releases map[string][]struct{} `json:"releases"`
+type ReleasePolicy struct {
+	MinAge            time.Duration
+	IncludePrerelease bool
+	QueryLatestCommit bool
 }
 
+type versionFilter func(version IVersion) bool
+
 func NewSematicVersion(version string, strict bool) (*semver.Version, error) {
 
 	semanticVersion, err := semver.NewVersion(version)
@@ -56,6 +82,27 @@ func NewSematicVersion(version string, strict bool) (*semver.Version, error) {
 	return semver.NewVersion(truncatedVersion[0])
 }
 
+func ParseReleaseAge(age string) (time.Duration, error) {
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if !strings.HasSuffix(age, suffix) {
+			continue
+		}
+		count, err := strconv.ParseFloat(strings.TrimSuffix(age, suffix), 64)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid release age %s", age)
+		}
+		return time.Duration(count * float64(unit)), nil
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid release age %s", age)
+	}
+	return duration, nil
+}
+
 func NewDependency(name, version string, strictSemVer bool) IDependable {
 
 	versionCurrent, err := NewSematicVersion(version, strictSemVer)
@@ -76,106 +123,468 @@ func NewDependency(name, version string, strictSemVer bool) IDependable {
 	}
 }
 
-func (d *Dependency) QueryReleaseVersions(language Language, wg *sync.WaitGroup) {
+func newParsedDependency[V IVersion](name, version string, parse func(string) (V, error)) IDependable {
+
+	versionCurrent, err := parse(version)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("%s %s", err.Error(), version))
+		return &Dependency{
+			Name:                  name,
+			VersionCurrentLiteral: version,
+		}
+	}
+
+	return &Dependency{
+		Name:                  name,
+		VersionCurrentLiteral: version,
+		VersionCurrent:        versionCurrent,
+	}
+}
+
+func NewGoDependency(name, version string, strictSemVer, indirect bool) IDependable {
+
+	dep := NewDependency(name, version, strictSemVer)
+	dep.(*Dependency).Indirect = indirect
+	return dep
+}
+
+func NewPythonDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewPep440Version)
+}
+
+func NewPythonConstraintDependency(name, constraint string, poetry bool) IDependable {
+
+	parseConstraint := NewPep440Constraint
+	if poetry {
+		parseConstraint = NewPoetryConstraint
+	}
+	versionConstraint, err := parseConstraint(constraint)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("%s %s", err.Error(), constraint))
+		return &Dependency{
+			Name:                  name,
+			VersionCurrentLiteral: constraint,
+		}
+	}
+
+	return &Dependency{
+		Name:                  name,
+		VersionCurrentLiteral: constraint,
+		Constraint:            versionConstraint,
+	}
+}
+
+func NewCondaDependency(name, version string, channels, subdirs []string) IDependable {
+
+	dep := newParsedDependency(name, version, NewCondaVersion).(*Dependency)
+	dep.Channels, dep.Subdirs = channels, subdirs
+	return dep
+}
+
+func NewJavascriptDependency(name, version string) IDependable {
+
+	return NewDependency(name, version, true)
+}
+
+func NewRustDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewCargoVersion)
+}
+
+func NewRubyDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewGemVersion)
+}
+
+func NewJavaDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewMavenVersion)
+}
+
+func NewPhpDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewComposerVersion)
+}
+
+func NewDotnetDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewNugetVersion)
+}
+
+func NewDockerDependency(name, version string) IDependable {
+
+	return newParsedDependency(name, version, NewDockerTag)
+}
+
+func NewActionsDependency(name, version string) IDependable {
+
+	return NewDockerDependency(name, version)
+}
+
+func NewTerraformDependency(name, version string) IDependable {
+
+	return NewDependency(name, version, true)
+}
+
+func NewTerraformConstraintDependency(name, constraint string) IDependable {
+
+	versionConstraint, err := NewTerraformConstraint(constraint)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("%s %s", err.Error(), constraint))
+		return &Dependency{
+			Name:                  name,
+			VersionCurrentLiteral: constraint,
+		}
+	}
+	if exact := versionConstraint.exactVersion(); exact != "" {
+		return NewTerraformDependency(name, exact)
+	}
+
+	return &Dependency{
+		Name:                  name,
+		VersionCurrentLiteral: constraint,
+		Constraint:            versionConstraint,
+	}
+}
+
+func NewHelmDependency(name, version, repository string) IDependable {
+
+	if !isHelmExactVersion(version) {
+		versionConstraint, err := NewHelmConstraint(version)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("%s %s", err.Error(), version))
+			return &Dependency{
+				Name:                  name,
+				VersionCurrentLiteral: version,
+				PackageIndex:          repository,
+			}
+		}
+		return &Dependency{
+			Name:                  name,
+			VersionCurrentLiteral: version,
+			Constraint:            versionConstraint,
+			PackageIndex:          repository,
+		}
+	}
+
+	dep := NewDependency(name, version, true)
+	dep.(*Dependency).PackageIndex = repository
+	return dep
+}
+
+func (d *Dependency) QueryReleaseVersions(language Language, policy ReleasePolicy, wg *sync.WaitGroup) {
 
 	defer wg.Done()
 
-	if d.VersionCurrent == nil {
+	if d.VersionCurrent == nil && d.Constraint == nil {
 		return
 	}
 
 	switch language {
 	case GO:
-		d.queryVersionsGo()
+		d.queryVersionsGo(policy)
 	case PYTHON:
-		d.queryVersionsPython()
+		d.queryVersionsPython(policy)
+	case CONDA:
+		if len(d.Channels) == 0 {
+			d.queryVersionsPython(policy)
+		} else {
+			d.queryVersionsConda(policy)
+		}
+	case JAVASCRIPT:
+		d.queryVersionsJavascript(policy)
+	case RUST:
+		d.queryVersionsRust(policy)
+	case RUBY:
+		d.queryVersionsRuby(policy)
+	case JAVA:
+		d.queryVersionsJava(policy)
+	case PHP:
+		d.queryVersionsPhp(policy)
+	case DOTNET:
+		d.queryVersionsDotnet(policy)
+	case DOCKER:
+		d.queryVersionsDocker(policy)
+	case ACTIONS:
+		d.queryVersionsActions(policy)
+	case TERRAFORM:
+		d.queryVersionsTerraform(policy)
+	case HELM:
+		d.queryVersionsHelm(policy)
 	default:
 		panic(fmt.Errorf("unsupported language %s", language.String()))
 	}
 }
 
-func getVersionsResponse(url string) *http.Response {
+func getVersionsResponse(url string, header http.Header) (*http.Response, error) {
 
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		logrus.Fatal(err.Error())
-		panic(fmt.Errorf("failed to build request with url %s", url))
+		return nil, fmt.Errorf("failed to build request with url %s: %w", url, err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
 	}
 	request.Header.Set("User-Agent", "GoMajor/1.0")
 
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
-		logrus.Fatal(err.Error())
-		panic(fmt.Errorf("failed to send request to url %s", url))
+		return nil, fmt.Errorf("failed to send request to url %s: %w", url, err)
 	}
-	return response
+	return response, nil
 }
 
-func getLatestVersion(versions []string, strictSemVer bool) *semver.Version {
+type registryResponse struct {
+	once       sync.Once
+	statusCode int
+	body       []byte
+}
+
+var registryResponses sync.Map
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+func queryRegistry(url string) (int, []byte) {
+
+	return queryRegistryWithHeader(url, nil)
+}
+
+// queryRegistryWithHeader additionally sends the given headers, e.g. to
+// negotiate the content type, the cache is still keyed by url only.
+func queryRegistryWithHeader(url string, header http.Header) (int, []byte) {
+
+	cached, _ := registryResponses.LoadOrStore(url, &registryResponse{})
+	entry := cached.(*registryResponse)
+	entry.once.Do(func() {
+		response, err := getVersionsResponse(url, header)
+		if err != nil {
+			logrus.Debug(err.Error())
+			return
+		}
+		defer response.Body.Close()
 
-	versionsAvailable := semver.Collection{}
+		entry.statusCode = response.StatusCode
+		entry.body, _ = io.ReadAll(response.Body)
+	})
+	return entry.statusCode, entry.body
+}
+
+func queryLastModified(url string) time.Time {
+
+	response, err := getVersionsResponse(url, nil)
+	if err != nil {
+		logrus.Debug(err.Error())
+		return time.Time{}
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return time.Time{}
+	}
+	published, _ := http.ParseTime(response.Header.Get("Last-Modified"))
+	return published
+}
+
+func nextPageUrl(header http.Header, base string) string {
+
+	link := nextLinkPattern.FindStringSubmatch(header.Get("Link"))
+	if link == nil {
+		return ""
+	}
+	if strings.HasPrefix(link[1], "/") {
+		return base + link[1]
+	}
+	return link[1]
+}
+
+func parseSemanticVersions(versions []string, strictSemVer bool) []IVersion {
+
+	parsedVersions := []IVersion{}
 	for _, ver := range versions {
 		newVersion, err := NewSematicVersion(strings.TrimSpace(ver), strictSemVer)
 		if err != nil {
 			logrus.Debug(fmt.Sprintf("invalid version %s", ver))
 			continue
 		}
+		parsedVersions = append(parsedVersions, newVersion)
+	}
+	return parsedVersions
+}
+
+func parsePep440Versions(versions []string) []IVersion {
 
-		versionsAvailable = append(versionsAvailable, newVersion)
+	parsedVersions := []IVersion{}
+	for _, ver := range versions {
+		newVersion, err := NewPep440Version(ver)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", ver))
+			continue
+		}
+		parsedVersions = append(parsedVersions, newVersion)
 	}
-	if len(versionsAvailable) == 0 {
+	return parsedVersions
+}
+
+func getLatestVersion(versions []string, strictSemVer bool, filters ...versionFilter) *semver.Version {
+
+	latest := findLatestVersion(parseSemanticVersions(versions, strictSemVer), filters...)
+	if latest == nil {
 		return nil
 	}
+	return latest.(*semver.Version)
+}
 
-	sort.Sort(versionsAvailable)
-	return versionsAvailable[len(versionsAvailable)-1]
+func findLatestVersion(versions []IVersion, filters ...versionFilter) IVersion {
+
+	sortedVersions := append([]IVersion{}, versions...)
+	sortVersions(sortedVersions)
+	for idx := len(sortedVersions) - 1; idx >= 0; idx-- {
+		if acceptVersion(sortedVersions[idx], filters) {
+			return sortedVersions[idx]
+		}
+	}
+	return nil
 }
 
-func (d *Dependency) queryVersionsGo() {
+func (d *Dependency) selectLatestVersions(versions []IVersion, policy ReleasePolicy, filters ...versionFilter) {
 
-	modulePath, err := module.EscapePath(d.Name)
-	if err != nil {
-		logrus.Fatal(err.Error())
-		panic(fmt.Errorf("failed to escape module path %s", d.Name))
+	currentPrerelease := d.VersionCurrent != nil && d.VersionCurrent.Prerelease() != ""
+	if policy.IncludePrerelease || (currentPrerelease && !d.IsUntagged()) {
+		d.VersionLatest = findLatestVersion(versions, filters...)
+		return
 	}
-	response := getVersionsResponse(fmt.Sprintf(proxyUrlGoModule, modulePath))
-	defer response.Body.Close()
 
-	versionsBytes, _ := io.ReadAll(response.Body)
-	versions := strings.Split(
-		strings.TrimSpace(
-			strings.ReplaceAll(string(versionsBytes), "\r\n", "\n"),
-		),
-		"\n",
-	)
-	d.VersionLatest = getLatestVersion(versions, d.StrictSemVer)
+	stableVersions := []IVersion{}
+	for _, ver := range versions {
+		if ver.Prerelease() == "" {
+			stableVersions = append(stableVersions, ver)
+		}
+	}
+	d.VersionLatest = findLatestVersion(stableVersions, filters...)
+
+	prerelease := findLatestVersion(versions, filters...)
+	if prerelease != nil && (d.VersionLatest == nil || compareVersions(prerelease, d.VersionLatest) > 0) {
+		d.VersionLatestPrerelease = prerelease
+	}
 }
 
-func (d *Dependency) queryVersionsPython() {
+func acceptVersion(version IVersion, filters []versionFilter) bool {
 
-	response := getVersionsResponse(fmt.Sprintf(proxyUrlPythonPackage, d.Name))
-	defer response.Body.Close()
+	for _, filter := range filters {
+		if !filter(version) {
+			return false
+		}
+	}
+	return true
+}
 
-	var pypiJson PypiJson
-	body, _ := io.ReadAll(response.Body)
-	err := json.Unmarshal(body, &pypiJson)
-	if err != nil {
-		return
+func filterReleaseAge(minAge time.Duration, releaseTime func(version IVersion) time.Time) versionFilter {
+
+	return func(version IVersion) bool {
+		if minAge <= 0 {
+			return true
+		}
+		published := releaseTime(version)
+		if published.IsZero() {
+			logrus.Debug(fmt.Sprintf("unknown release time of version %s", version.Original()))
+			return false
+		}
+		return time.Since(published) >= minAge
 	}
+}
+
+func (d *Dependency) MatchQualifier(qualifier string) bool {
+
+	switch qualifier {
+	case "direct":
+		return !d.Indirect
+	case "indirect":
+		return d.Indirect
+	case mainGroup:
+		return d.InGroups([]string{mainGroup})
+	case devGroup:
+		return d.IsDevelopment()
+	default:
+		return false
+	}
+}
+
+func (d *Dependency) InGroups(groups []string) bool {
+
+	for _, group := range d.Groups {
+		for _, wanted := range groups {
+			if group == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (d *Dependency) IsDevelopment() bool {
+
+	return len(d.Groups) > 0 && !d.InGroups([]string{mainGroup})
+}
+
+func (d *Dependency) GetWarnings() []string {
 
-	versions := []string{}
-	for ver := range pypiJson.Releases {
-		versions = append(versions, ver)
+	warnings := []string{}
+	if d.Retracted && d.RetractedRationale == "" {
+		warnings = append(warnings, "retracted")
+	} else if d.Retracted {
+		warnings = append(warnings, fmt.Sprintf("retracted: %s", d.RetractedRationale))
+	}
+	if d.Deprecated != "" {
+		warnings = append(warnings, fmt.Sprintf("deprecated: %s", d.Deprecated))
 	}
-	d.VersionLatest = getLatestVersion(versions, d.StrictSemVer)
+	if d.Yanked && d.YankedReason == "" {
+		warnings = append(warnings, "yanked")
+	} else if d.Yanked {
+		warnings = append(warnings, fmt.Sprintf("yanked: %s", d.YankedReason))
+	}
+	if d.Unpinned && d.VersionCurrentLiteral == "latest" {
+		warnings = append(warnings, "floating latest tag")
+	}
+	return warnings
 }
 
 func (d *Dependency) GetOutdatedScope() OutdatedScope {
 
 	current, latest := d.VersionCurrent, d.VersionLatest
+	if d.LocalPath != "" {
+		return LOCAL
+	}
+	if d.Unpinned {
+		return UNPINNED
+	}
+	if d.Constraint != nil {
+		return d.getConstraintScope()
+	}
+	if d.IsUntagged() {
+		return UNTAGGED
+	}
 	if current == nil || latest == nil {
 		return UNKNOWN
 	}
+	if current, ok := current.(IScopedVersion); ok {
+		return current.OutdatedScope(latest)
+	}
+	return getSemanticScope(current, latest)
+}
+
+func (d *Dependency) getConstraintScope() OutdatedScope {
+
+	if d.VersionLatest == nil {
+		return UNKNOWN
+	}
+	if d.Constraint.Admits(d.VersionLatest) {
+		return UP_TO_DATE
+	}
+	return CONSTRAINT_OUTDATED
+}
+
+func getSemanticScope(current, latest IVersion) OutdatedScope {
+
 	if latest.Major() > current.Major() {
 		return MAJOR
 	}
@@ -187,3 +596,14 @@ func (d *Dependency) GetOutdatedScope() OutdatedScope {
 	}
 	return UP_TO_DATE
 }
+
+func getExtendedScope(current, latest IVersion) OutdatedScope {
+
+	if compareVersions(current, latest) >= 0 {
+		return UP_TO_DATE
+	}
+	if scope := getSemanticScope(current, latest); scope != UP_TO_DATE {
+		return scope
+	}
+	return PATCH
+}
@@ -1,7 +1,13 @@
 package telescope
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +29,7 @@ func TestNewSemanticVersion(t *testing.T) {
 		{name: "beta tag", version: "v1.0.0b2", strict: false, expected: "v1.0.0"},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -49,6 +56,7 @@ func TestNewSemanticVersionError(t *testing.T) {
 		{name: "strict beta tag", version: "v1.0.0b2", strict: true},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -77,6 +85,7 @@ func TestGetLatestVersion(t *testing.T) {
 		{name: "different versions 2", versions: []string{"v1.1.0", "v2.0.0"}, expected: "v2.0.0"},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -90,6 +99,156 @@ func TestGetLatestVersion(t *testing.T) {
 	}
 }
 
+func TestGetLatestVersionReleaseAge(t *testing.T) {
+
+	releaseTimes := map[string]time.Time{
+		"v1.0.0": time.Now().Add(-30 * 24 * time.Hour),
+		"v1.1.0": time.Now().Add(-2 * 24 * time.Hour),
+	}
+	releaseTime := func(version IVersion) time.Time {
+		return releaseTimes[version.Original()]
+	}
+	params := []struct {
+		name     string
+		versions []string
+		minAge   time.Duration
+		expected string
+	}{
+		{name: "no cooldown", versions: []string{"v1.0.0", "v1.1.0"}, minAge: 0, expected: "v1.1.0"},
+		{name: "fresh release", versions: []string{"v1.0.0", "v1.1.0"}, minAge: 7 * 24 * time.Hour, expected: "v1.0.0"},
+		{name: "unknown release time", versions: []string{"v1.0.0", "v2.0.0"}, minAge: 7 * 24 * time.Hour, expected: "v1.0.0"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				ver_obtained := getLatestVersion(param.versions, true, filterReleaseAge(param.minAge, releaseTime))
+				ver_expected, _ := semver.NewVersion(param.expected)
+				assert.Equal(t, ver_obtained, ver_expected)
+			},
+		)
+	}
+
+	assert.Nil(t, getLatestVersion([]string{"v1.1.0"}, true, filterReleaseAge(7*24*time.Hour, releaseTime)))
+}
+
+func TestSelectLatestVersions(t *testing.T) {
+
+	versions := parseSemanticVersions([]string{"v1.0.0", "v1.1.0", "v2.0.0-beta.1"}, true)
+	params := []struct {
+		name               string
+		current            string
+		policy             ReleasePolicy
+		expectedLatest     string
+		expectedPrerelease string
+	}{
+		{name: "stable current", current: "v1.0.0", policy: ReleasePolicy{}, expectedLatest: "v1.1.0", expectedPrerelease: "v2.0.0-beta.1"},
+		{name: "prerelease current", current: "v2.0.0-alpha.1", policy: ReleasePolicy{}, expectedLatest: "v2.0.0-beta.1"},
+		{name: "include prerelease", current: "v1.0.0", policy: ReleasePolicy{IncludePrerelease: true}, expectedLatest: "v2.0.0-beta.1"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				dep := NewDependency("module", param.current, true).(*Dependency)
+				dep.selectLatestVersions(versions, param.policy)
+				latest, _ := semver.NewVersion(param.expectedLatest)
+				assert.Equal(t, dep.VersionLatest, latest)
+				if param.expectedPrerelease == "" {
+					assert.Nil(t, dep.VersionLatestPrerelease)
+					return
+				}
+				prerelease, _ := semver.NewVersion(param.expectedPrerelease)
+				assert.Equal(t, dep.VersionLatestPrerelease, prerelease)
+			},
+		)
+	}
+}
+
+func TestSelectLatestVersionsPep440(t *testing.T) {
+
+	dep := NewPythonDependency("package", "1.0.0").(*Dependency)
+	dep.selectLatestVersions(parsePep440Versions([]string{"1.0.0", "1.1.0", "2.0.0rc1", "invalid"}), ReleasePolicy{})
+	assert.Equal(t, dep.VersionLatest.String(), "1.1.0")
+	assert.Equal(t, dep.VersionLatestPrerelease.String(), "2.0.0rc1")
+	assert.Equal(t, dep.GetOutdatedScope(), MINOR)
+}
+
+func TestSelectLatestVersionsConstraint(t *testing.T) {
+
+	dep := NewPythonConstraintDependency("package", "^1.0", true).(*Dependency)
+	dep.selectLatestVersions(parsePep440Versions([]string{"1.0.0", "1.1.0", "2.0.0"}), ReleasePolicy{})
+	assert.Equal(t, dep.VersionLatest.String(), "2.0.0")
+	assert.Equal(t, dep.GetOutdatedScope(), CONSTRAINT_OUTDATED)
+
+	invalid := NewPythonConstraintDependency("package", "^foo", true).(*Dependency)
+	assert.Nil(t, invalid.Constraint)
+	assert.Equal(t, invalid.GetOutdatedScope(), UNKNOWN)
+}
+
+func TestParseReleaseAge(t *testing.T) {
+
+	params := []struct {
+		name     string
+		age      string
+		expected time.Duration
+	}{
+		{name: "go duration", age: "12h", expected: 12 * time.Hour},
+		{name: "days", age: "7d", expected: 7 * 24 * time.Hour},
+		{name: "weeks", age: "2w", expected: 14 * 24 * time.Hour},
+		{name: "zero", age: "0s", expected: 0},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				age, err := ParseReleaseAge(param.age)
+				assert.Nil(t, err)
+				assert.Equal(t, age, param.expected)
+			},
+		)
+	}
+
+	for _, age := range []string{"", "7", "-1d", "xd"} {
+		_, err := ParseReleaseAge(age)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestQueryRegistry(t *testing.T) {
+
+	var hits int32
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			fmt.Fprint(w, "v1.0.0\n")
+		}),
+	)
+	defer server.Close()
+
+	queryWaitGroup := new(sync.WaitGroup)
+	queryWaitGroup.Add(3)
+	for idx := 0; idx < 3; idx++ {
+		go func() {
+			defer queryWaitGroup.Done()
+			statusCode, body := queryRegistry(server.URL + "/list")
+			assert.Equal(t, statusCode, http.StatusOK)
+			assert.Equal(t, string(body), "v1.0.0\n")
+		}()
+	}
+	queryWaitGroup.Wait()
+	assert.Equal(t, atomic.LoadInt32(&hits), int32(1))
+}
+
 func TestGetOutdatedScope(t *testing.T) {
 
 	params := []struct {
@@ -104,6 +263,7 @@ func TestGetOutdatedScope(t *testing.T) {
 		{name: "patch", versionCurrent: "v1.0.0", versionLatest: "v1.0.1", expected: PATCH},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -0,0 +1,374 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultOciRegistry = "https://registry-1.docker.io"
+	ociTagsUrl         = "%s/v2/%s/tags/list"
+)
+
+var OciRegistry = defaultOciRegistry
+
+var (
+	dockerTagPattern      = regexp.MustCompile(`^v?([0-9]+(?:\.[0-9]+){0,3})(?:[-_](.+))?$`)
+	dockerArgPattern      = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+	ociChallengeParameter = regexp.MustCompile(`([a-z]+)="([^"]*)"`)
+)
+
+type DockerTag struct {
+	original string
+	numbers  []int64
+	variant  string
+}
+
+type DockerfileImage struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+type OciTags struct {
+	Tags []string `json:"tags"`
+}
+
+func NewDockerTag(tag string) (*DockerTag, error) {
+
+	match := dockerTagPattern.FindStringSubmatch(strings.TrimSpace(tag))
+	if match == nil {
+		return nil, fmt.Errorf("invalid docker tag string %s", tag)
+	}
+
+	dockerTag := &DockerTag{original: tag, variant: match[2]}
+	for _, digits := range strings.Split(match[1], ".") {
+		number, _ := strconv.ParseInt(digits, 10, 64)
+		dockerTag.numbers = append(dockerTag.numbers, number)
+	}
+	return dockerTag, nil
+}
+
+func (v *DockerTag) Original() string {
+	return v.original
+}
+
+func (v *DockerTag) String() string {
+	return v.original
+}
+
+func (v *DockerTag) number(idx int) int64 {
+
+	if idx < len(v.numbers) {
+		return v.numbers[idx]
+	}
+	return 0
+}
+
+func (v *DockerTag) Major() int64 {
+	return v.number(0)
+}
+
+func (v *DockerTag) Minor() int64 {
+	return v.number(1)
+}
+
+func (v *DockerTag) Patch() int64 {
+	return v.number(2)
+}
+
+// Prerelease is always empty, tags such as 1.22rc1 are not version tags and
+// those such as 1.22-rc1 make a variant of their own.
+func (v *DockerTag) Prerelease() string {
+	return ""
+}
+
+func (v *DockerTag) Compare(other IVersion) int {
+
+	o := other.(*DockerTag)
+	for idx := 0; idx < len(v.numbers) || idx < len(o.numbers); idx++ {
+		if result := compareInt64(v.number(idx), o.number(idx)); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func (v *DockerTag) OutdatedScope(latest IVersion) OutdatedScope {
+
+	return getExtendedScope(v, latest)
+}
+
+func (v *DockerTag) matches(other *DockerTag) bool {
+
+	return v.variant == other.variant && len(v.numbers) == len(other.numbers)
+}
+
+func (d *Dependency) queryVersionsDocker(policy ReleasePolicy) {
+
+	if policy.MinAge > 0 {
+		logrus.Warn(fmt.Sprintf("skip --min-age for image %s: unknown release time", d.Name))
+	}
+	current, ok := d.VersionCurrent.(*DockerTag)
+	if !ok {
+		return
+	}
+
+	host, repository := ociRepository(d.Name)
+	registry := OciRegistry
+	if registryUrl, err := url.Parse(OciRegistry); host != "" && (err != nil || registryUrl.Host != host) {
+		registry = "https://" + host
+	}
+	tags, ok := queryOciTags(strings.TrimRight(registry, "/"), repository)
+	if !ok {
+		logrus.Debug(fmt.Sprintf("failed to query tags of %s", d.Name))
+		return
+	}
+
+	versions := []IVersion{}
+	for _, tag := range tags {
+		dockerTag, err := NewDockerTag(tag)
+		if err != nil || !current.matches(dockerTag) {
+			continue
+		}
+		versions = append(versions, dockerTag)
+	}
+	d.selectLatestVersions(versions, policy)
+}
+
+func queryOciTags(registry, repository string) ([]string, bool) {
+
+	tags := []string{}
+	header := http.Header{}
+	tagsUrl := fmt.Sprintf(ociTagsUrl, registry, repository)
+	for tagsUrl != "" {
+		response, err := getVersionsResponse(tagsUrl, header)
+		if err != nil {
+			logrus.Debug(err.Error())
+			return nil, false
+		}
+		if response.StatusCode == http.StatusUnauthorized && header.Get("Authorization") == "" {
+			token := queryOciToken(response.Header.Get("Www-Authenticate"), repository)
+			response.Body.Close()
+			if token == "" {
+				return nil, false
+			}
+			header.Set("Authorization", "Bearer "+token)
+			continue
+		}
+
+		var ociTags OciTags
+		err = json.NewDecoder(response.Body).Decode(&ociTags)
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK || err != nil {
+			return nil, false
+		}
+		tags = append(tags, ociTags.Tags...)
+		tagsUrl = nextPageUrl(response.Header, registry)
+	}
+	return tags, true
+}
+
+func queryOciToken(challenge, repository string) string {
+
+	scheme, parameters, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	params := map[string]string{}
+	for _, match := range ociChallengeParameter.FindAllStringSubmatch(parameters, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return ""
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", repository)
+	}
+	query := url.Values{"service": {params["service"]}, "scope": {scope}}
+
+	response, err := getVersionsResponse(params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		logrus.Debug(err.Error())
+		return ""
+	}
+	defer response.Body.Close()
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if response.StatusCode != http.StatusOK || json.NewDecoder(response.Body).Decode(&token) != nil {
+		return ""
+	}
+	if token.Token != "" {
+		return token.Token
+	}
+	return token.AccessToken
+}
+
+func ociRepository(name string) (string, string) {
+
+	host, repository, found := strings.Cut(name, "/")
+	if !found || !(strings.ContainsAny(host, ".:") || host == "localhost") {
+		host, repository = "", name
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = ""
+	}
+	if host == "" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return host, repository
+}
+
+func parseDockerfile(content string) []DockerfileImage {
+
+	images := []DockerfileImage{}
+	arguments := map[string]string{}
+	stages := map[string]bool{"scratch": true}
+	staged := false
+	for _, instruction := range splitDockerfileInstructions(content) {
+		fields := strings.Fields(instruction)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			if staged {
+				continue
+			}
+			for _, argument := range fields[1:] {
+				if name, value, found := strings.Cut(argument, "="); found {
+					arguments[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			staged = true
+			operands := fields[1:]
+			for len(operands) > 0 && strings.HasPrefix(operands[0], "--") {
+				operands = operands[1:]
+			}
+			if len(operands) == 0 {
+				continue
+			}
+			reference := substituteDockerArguments(operands[0], arguments)
+			if !stages[strings.ToLower(reference)] {
+				images = append(images, parseImageReference(reference))
+			}
+			if len(operands) >= 3 && strings.EqualFold(operands[1], "AS") {
+				stages[strings.ToLower(operands[2])] = true
+			}
+		}
+	}
+	return images
+}
+
+func splitDockerfileInstructions(content string) []string {
+
+	instructions := []string{}
+	var instruction strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if continued := strings.TrimSuffix(trimmed, "\\"); continued != trimmed {
+			instruction.WriteString(continued + " ")
+			continue
+		}
+		instruction.WriteString(trimmed)
+		if strings.TrimSpace(instruction.String()) != "" {
+			instructions = append(instructions, strings.TrimSpace(instruction.String()))
+		}
+		instruction.Reset()
+	}
+	if strings.TrimSpace(instruction.String()) != "" {
+		instructions = append(instructions, strings.TrimSpace(instruction.String()))
+	}
+	return instructions
+}
+
+func substituteDockerArguments(value string, arguments map[string]string) string {
+
+	return dockerArgPattern.ReplaceAllStringFunc(
+		value,
+		func(reference string) string {
+			match := dockerArgPattern.FindStringSubmatch(reference)
+			name := match[1] + match[3]
+			if argument, ok := arguments[name]; ok && argument != "" {
+				return argument
+			}
+			if strings.Contains(reference, ":-") {
+				return match[2]
+			}
+			return reference
+		},
+	)
+}
+
+func parseImageReference(reference string) DockerfileImage {
+
+	name, digest, _ := strings.Cut(reference, "@")
+	image := DockerfileImage{Name: name, Digest: digest}
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		image.Name, image.Tag = name[:idx], name[idx+1:]
+	}
+	return image
+}
+
+func buildAtlasDockerfile(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	atlas := Atlas{
+		name:         "",
+		language:     DOCKER,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	reported := map[DockerfileImage]bool{}
+	for _, image := range parseDockerfile(string(fileBytes)) {
+		if reported[image] || matchRegExpPatterns(ignoredPatterns, image.Name) {
+			continue
+		}
+		reported[image] = true
+
+		var dep *Dependency
+		switch {
+		case strings.Contains(image.Name+image.Tag, "$"):
+			// arguments without default are given at build time
+			dep = &Dependency{Name: image.Name, VersionCurrentLiteral: image.Tag}
+		case image.Tag == "" && image.Digest != "":
+			dep = &Dependency{Name: image.Name, VersionCurrentLiteral: "@" + image.Digest}
+		case image.Tag == "":
+			dep = &Dependency{Name: image.Name, VersionCurrentLiteral: "latest", Unpinned: true}
+		default:
+			dep = NewDockerDependency(image.Name, image.Tag).(*Dependency)
+			dep.Unpinned = dep.VersionCurrent == nil && image.Digest == ""
+		}
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
+
+func isDockerfile(fileName string) bool {
+
+	for _, baseName := range []string{"Dockerfile", "Containerfile"} {
+		if fileName == baseName || strings.HasPrefix(fileName, baseName+".") || strings.HasSuffix(fileName, "."+baseName) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package telescope
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerTagCompare(t *testing.T) {
+
+	params := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.19.3-alpine", b: "1.21.0-alpine", expected: -1},
+		{a: "3.18", b: "3.9", expected: 1},
+		{a: "v1.2.3", b: "1.2.3", expected: 0},
+		{a: "20.04", b: "22.04", expected: -1},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.a+" "+param.b,
+			func(t *testing.T) {
+				t.Parallel()
+				a, _ := NewDockerTag(param.a)
+				b, _ := NewDockerTag(param.b)
+				assert.Equal(t, a.Compare(b), param.expected)
+			},
+		)
+	}
+}
+
+func TestDockerTagMatches(t *testing.T) {
+
+	params := []struct {
+		current  string
+		tag      string
+		expected bool
+	}{
+		{current: "1.19.3-alpine", tag: "1.21.0-alpine", expected: true},
+		{current: "1.19.3-alpine", tag: "1.21.0", expected: false},
+		{current: "1.19.3-alpine", tag: "1.21-alpine", expected: false},
+		{current: "1.19.3-alpine", tag: "1.21.0-alpine3.18", expected: false},
+		{current: "3.18", tag: "3.19", expected: true},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.current+" "+param.tag,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewDockerTag(param.current)
+				tag, _ := NewDockerTag(param.tag)
+				assert.Equal(t, current.matches(tag), param.expected)
+			},
+		)
+	}
+}
+
+func TestOciRepository(t *testing.T) {
+
+	params := []struct {
+		name       string
+		host       string
+		repository string
+	}{
+		{name: "golang", host: "", repository: "library/golang"},
+		{name: "bitnami/redis", host: "", repository: "bitnami/redis"},
+		{name: "docker.io/library/alpine", host: "", repository: "library/alpine"},
+		{name: "ghcr.io/org/app", host: "ghcr.io", repository: "org/app"},
+		{name: "localhost:5000/app", host: "localhost:5000", repository: "app"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				host, repository := ociRepository(param.name)
+				assert.Equal(t, host, param.host)
+				assert.Equal(t, repository, param.repository)
+			},
+		)
+	}
+}
+
+func TestParseDockerfile(t *testing.T) {
+
+	images := parseDockerfile(`# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.19.3
+ARG BASE
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION}-alpine AS builder
+RUN go build \
+    -o /bin/app .
+
+FROM builder AS test
+FROM ${BASE:-alpine}:3.18@sha256:1a2b3c
+FROM scratch
+COPY --from=builder /bin/app /app
+`)
+
+	assert.Equal(
+		t,
+		images,
+		[]DockerfileImage{
+			{Name: "golang", Tag: "1.19.3-alpine"},
+			{Name: "alpine", Tag: "3.18", Digest: "sha256:1a2b3c"},
+		},
+	)
+}
+
+func TestQueryVersionsDocker(t *testing.T) {
+
+	var server *httptest.Server
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/token":
+				assert.Equal(t, r.URL.Query().Get("scope"), "repository:library/golang:pull")
+				w.Write([]byte(`{"token": "anonymous"}`))
+			case r.Header.Get("Authorization") != "Bearer anonymous":
+				w.Header().Set(
+					"Www-Authenticate",
+					fmt.Sprintf(`Bearer realm="%s/token",service="registry.example.com",scope="repository:library/golang:pull"`, server.URL),
+				)
+				w.WriteHeader(http.StatusUnauthorized)
+			case r.URL.Path == "/v2/library/golang/tags/list" && r.URL.Query().Get("last") == "":
+				w.Header().Set("Link", `</v2/library/golang/tags/list?last=1.20.0&n=4>; rel="next"`)
+				w.Write([]byte(`{"name": "library/golang", "tags": ["1.19.3", "1.19.3-alpine", "1.20-alpine", "1.20.0"]}`))
+			case r.URL.Path == "/v2/library/golang/tags/list":
+				w.Write([]byte(`{"name": "library/golang", "tags": ["1.20.0-alpine", "1.21.1-alpine", "1.22rc1-alpine", "latest"]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	registry := OciRegistry
+	OciRegistry = server.URL
+	defer func() { OciRegistry = registry }()
+
+	golang := NewDockerDependency("golang", "1.19.3-alpine").(*Dependency)
+	golang.queryVersionsDocker(ReleasePolicy{})
+	assert.Equal(t, golang.VersionLatest.String(), "1.21.1-alpine")
+	assert.Equal(t, golang.GetOutdatedScope(), MINOR)
+
+	cooled := NewDockerDependency("golang", "1.19.3-alpine").(*Dependency)
+	cooled.queryVersionsDocker(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, cooled.VersionLatest.String(), "1.21.1-alpine")
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	unreachable := NewDockerDependency(strings.TrimPrefix(closed.URL, "http://")+"/app", "1.0.0").(*Dependency)
+	unreachable.queryVersionsDocker(ReleasePolicy{})
+	assert.Nil(t, unreachable.VersionLatest)
+	assert.Equal(t, unreachable.GetOutdatedScope(), UNKNOWN)
+}
+
+func TestBuildAtlasDockerfile(t *testing.T) {
+
+	atlas := buildAtlasDockerfile(
+		[]byte(`ARG NODE_VERSION=18.17.1
+FROM golang:1.19.3-alpine as builder
+FROM node:${NODE_VERSION}-bookworm-slim AS assets
+FROM golang:1.19.3-alpine as tester
+FROM alpine:latest
+FROM debian:bookworm@sha256:1a2b3c
+FROM ubuntu
+FROM registry.example.com/base@sha256:4d5e6f
+FROM ${RUNTIME_IMAGE}
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, DOCKER)
+	assert.Equal(t, len(atlas.dependencies), 7)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "1.19.3-alpine")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrentLiteral, "18.17.1-bookworm-slim")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetWarnings(), []string{"floating latest tag"})
+	assert.False(t, atlas.dependencies[3].(*Dependency).Unpinned)
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetWarnings(), []string{})
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).VersionCurrentLiteral, "latest")
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).GetWarnings(), []string{"floating latest tag"})
+	assert.Equal(t, atlas.dependencies[5].(*Dependency).VersionCurrentLiteral, "@sha256:4d5e6f")
+	assert.Equal(t, atlas.dependencies[6].(*Dependency).GetOutdatedScope(), UNKNOWN)
+}
@@ -0,0 +1,289 @@
+package telescope
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNugetFlatContainer = "https://api.nuget.org/v3-flatcontainer"
+	nugetPackageUrl           = "%s/%s"
+)
+
+var NugetFlatContainer = defaultNugetFlatContainer
+
+var (
+	nugetVersionPattern  = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+){0,3}(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?(?:\+[0-9A-Za-z.-]+)?$`)
+	msbuildPropertyRegex = regexp.MustCompile(`\$\(([^)]+)\)`)
+)
+
+type NugetVersion struct {
+	original string
+	numbers  [4]int64
+	release  []string
+}
+
+type NugetVersions struct {
+	Versions []string `json:"versions"`
+}
+
+type NugetLockPackage struct {
+	Type      string `json:"type"`
+	Requested string `json:"requested"`
+	Resolved  string `json:"resolved"`
+}
+
+type NugetLock struct {
+	Version      int                                    `json:"version"`
+	Dependencies map[string]map[string]NugetLockPackage `json:"dependencies"`
+}
+
+type NugetPackageVersion struct {
+	Include string `xml:"Include,attr"`
+	Version string `xml:"Version,attr"`
+}
+
+type DirectoryPackagesProps struct {
+	PropertyGroups []struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"PropertyGroup"`
+	PackageVersions         []NugetPackageVersion `xml:"ItemGroup>PackageVersion"`
+	GlobalPackageReferences []NugetPackageVersion `xml:"ItemGroup>GlobalPackageReference"`
+}
+
+func NewNugetVersion(version string) (*NugetVersion, error) {
+
+	normalized := strings.TrimSpace(version)
+	if !nugetVersionPattern.MatchString(normalized) {
+		return nil, fmt.Errorf("invalid nuget version string %s", version)
+	}
+
+	normalized, _, _ = strings.Cut(normalized, "+")
+	numbers, release, _ := strings.Cut(normalized, "-")
+	nugetVersion := &NugetVersion{original: version}
+	for idx, digits := range strings.Split(numbers, ".") {
+		nugetVersion.numbers[idx], _ = strconv.ParseInt(digits, 10, 64)
+	}
+	if release != "" {
+		nugetVersion.release = strings.Split(release, ".")
+	}
+	return nugetVersion, nil
+}
+
+func (v *NugetVersion) Original() string {
+	return v.original
+}
+
+func (v *NugetVersion) String() string {
+	return v.original
+}
+
+func (v *NugetVersion) Major() int64 {
+	return v.numbers[0]
+}
+
+func (v *NugetVersion) Minor() int64 {
+	return v.numbers[1]
+}
+
+func (v *NugetVersion) Patch() int64 {
+	return v.numbers[2]
+}
+
+func (v *NugetVersion) Prerelease() string {
+	return strings.Join(v.release, ".")
+}
+
+func (v *NugetVersion) Compare(other IVersion) int {
+
+	o := other.(*NugetVersion)
+	for idx := range v.numbers {
+		if result := compareInt64(v.numbers[idx], o.numbers[idx]); result != 0 {
+			return result
+		}
+	}
+	switch {
+	case len(v.release) == 0 && len(o.release) == 0:
+		return 0
+	case len(v.release) == 0:
+		return 1
+	case len(o.release) == 0:
+		return -1
+	}
+	for idx := 0; idx < len(v.release) && idx < len(o.release); idx++ {
+		a, errA := strconv.ParseInt(v.release[idx], 10, 64)
+		b, errB := strconv.ParseInt(o.release[idx], 10, 64)
+		var result int
+		switch {
+		case errA == nil && errB == nil:
+			result = compareInt64(a, b)
+		case errA == nil:
+			result = -1
+		case errB == nil:
+			result = 1
+		default:
+			result = strings.Compare(strings.ToLower(v.release[idx]), strings.ToLower(o.release[idx]))
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return compareInt64(int64(len(v.release)), int64(len(o.release)))
+}
+
+func (v *NugetVersion) OutdatedScope(latest IVersion) OutdatedScope {
+
+	return getExtendedScope(v, latest)
+}
+
+func (d *Dependency) queryVersionsDotnet(policy ReleasePolicy) {
+
+	id := strings.ToLower(d.Name)
+	packageUrl := fmt.Sprintf(nugetPackageUrl, strings.TrimRight(NugetFlatContainer, "/"), id)
+	statusCode, body := queryRegistry(packageUrl + "/index.json")
+	var nugetVersions NugetVersions
+	if statusCode != http.StatusOK || json.Unmarshal(body, &nugetVersions) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query nuget versions of %s", packageUrl))
+		return
+	}
+
+	versions := []IVersion{}
+	for _, ver := range nugetVersions.Versions {
+		nugetVersion, err := NewNugetVersion(ver)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", ver))
+			continue
+		}
+		versions = append(versions, nugetVersion)
+	}
+
+	// the publication time is taken from the nuspec of the candidate versions
+	releaseTimes := map[string]time.Time{}
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				if _, ok := releaseTimes[version.Original()]; !ok {
+					releaseTimes[version.Original()] = queryLastModified(
+						fmt.Sprintf("%s/%s/%s.nuspec", packageUrl, strings.ToLower(version.Original()), id),
+					)
+				}
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func isNugetRange(version string) bool {
+
+	return strings.ContainsAny(version, "[]()*,")
+}
+
+func interpolateMsbuildProperties(value string, properties map[string]string) string {
+
+	return msbuildPropertyRegex.ReplaceAllStringFunc(
+		strings.TrimSpace(value),
+		func(reference string) string {
+			if property, ok := properties[reference[2:len(reference)-1]]; ok {
+				return property
+			}
+			return reference
+		},
+	)
+}
+
+func buildAtlasNugetLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var nugetLock NugetLock
+	err := json.Unmarshal(fileBytes, &nugetLock)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     DOTNET,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	mergedDependencies := map[string]*Dependency{}
+	for _, framework := range sortedKeys(nugetLock.Dependencies) {
+		packages := nugetLock.Dependencies[framework]
+		for _, name := range sortedKeys(packages) {
+			pkg := packages[name]
+			if pkg.Type == "Project" || matchRegExpPatterns(ignoredPatterns, name) {
+				continue
+			}
+
+			key := strings.ToLower(name) + " " + pkg.Resolved
+			if merged, ok := mergedDependencies[key]; ok {
+				merged.Indirect = merged.Indirect && pkg.Type != "Direct"
+				continue
+			}
+			dep := NewDotnetDependency(name, pkg.Resolved).(*Dependency)
+			dep.Indirect = pkg.Type != "Direct"
+			mergedDependencies[key] = dep
+			atlas.appendDependency(dep)
+		}
+	}
+	return &atlas
+}
+
+func buildAtlasDirectoryPackagesProps(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var props DirectoryPackagesProps
+	err := xml.Unmarshal(fileBytes, &props)
+	if err != nil {
+		panic(err)
+	}
+	properties := map[string]string{}
+	for _, propertyGroup := range props.PropertyGroups {
+		for _, entry := range propertyGroup.Entries {
+			properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+		}
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     DOTNET,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	for _, packageVersion := range append(props.PackageVersions, props.GlobalPackageReferences...) {
+		if matchRegExpPatterns(ignoredPatterns, packageVersion.Include) {
+			continue
+		}
+
+		version := interpolateMsbuildProperties(packageVersion.Version, properties)
+		// unresolved property references are left to the unknown versions
+		if isNugetRange(version) && !strings.Contains(version, "$(") {
+			atlas.appendDependency(&Dependency{Name: packageVersion.Include, VersionCurrentLiteral: version, Unpinned: true})
+			continue
+		}
+		atlas.appendDependency(NewDotnetDependency(packageVersion.Include, version))
+	}
+	return &atlas
+}
@@ -0,0 +1,161 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNugetVersionCompare(t *testing.T) {
+
+	params := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0", b: "1.0.0.0", expected: 0},
+		{a: "1.0.0+build.1", b: "1.0.0", expected: 0},
+		{a: "1.0.0-RC.1", b: "1.0.0-rc.1", expected: 0},
+		{a: "1.0.0-beta", b: "1.0.0", expected: -1},
+		{a: "1.0.0-beta.2", b: "1.0.0-beta.10", expected: -1},
+		{a: "1.0.0-1", b: "1.0.0-alpha", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", expected: -1},
+		{a: "4.0.0.1", b: "4.0.0", expected: 1},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.a+" "+param.b,
+			func(t *testing.T) {
+				t.Parallel()
+				a, _ := NewNugetVersion(param.a)
+				b, _ := NewNugetVersion(param.b)
+				assert.Equal(t, a.Compare(b), param.expected)
+			},
+		)
+	}
+}
+
+func TestNugetVersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{current: "12.0.3", latest: "13.0.1", expected: MAJOR},
+		{current: "6.0.0", latest: "6.1.0", expected: MINOR},
+		{current: "4.3.0", latest: "4.3.0.1", expected: PATCH},
+		{current: "8.0.0-rc.2", latest: "8.0.0", expected: PATCH},
+		{current: "13.0.1", latest: "13.0.1", expected: UP_TO_DATE},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.current+" "+param.latest,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewNugetVersion(param.current)
+				latest, _ := NewNugetVersion(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestQueryVersionsDotnet(t *testing.T) {
+
+	published := time.Now().Add(-48 * time.Hour).UTC().Format(http.TimeFormat)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/newtonsoft.json/index.json":
+				w.Write([]byte(`{"versions": ["12.0.3", "13.0.1", "13.0.2", "13.0.3", "14.0.1-beta1"]}`))
+			case "/newtonsoft.json/13.0.3/newtonsoft.json.nuspec":
+				w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			case "/newtonsoft.json/13.0.2/newtonsoft.json.nuspec", "/newtonsoft.json/14.0.1-beta1/newtonsoft.json.nuspec":
+				w.Header().Set("Last-Modified", published)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	flatContainer := NugetFlatContainer
+	NugetFlatContainer = server.URL
+	defer func() { NugetFlatContainer = flatContainer }()
+
+	newtonsoft := NewDotnetDependency("Newtonsoft.Json", "12.0.3").(*Dependency)
+	newtonsoft.queryVersionsDotnet(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, newtonsoft.VersionLatest.String(), "13.0.2")
+	assert.Equal(t, newtonsoft.VersionLatestPrerelease.String(), "14.0.1-beta1")
+	assert.Equal(t, newtonsoft.GetOutdatedScope(), MAJOR)
+}
+
+func TestBuildAtlasNugetLock(t *testing.T) {
+
+	atlas := buildAtlasNugetLock(
+		[]byte(`{
+	"version": 1,
+	"dependencies": {
+		"net6.0": {
+			"Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1"},
+			"Serilog": {"type": "Transitive", "resolved": "2.12.0"},
+			"Shared": {"type": "Project"}
+		},
+		"net8.0": {
+			"Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.1, )", "resolved": "13.0.1"},
+			"Serilog": {"type": "CentralTransitive", "requested": "[3.1.1, )", "resolved": "3.1.1"}
+		}
+	}
+}`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, DOTNET)
+	assert.Equal(t, len(atlas.dependencies), 3)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).Name, "Newtonsoft.Json")
+	assert.False(t, atlas.dependencies[0].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrent.String(), "2.12.0")
+	assert.True(t, atlas.dependencies[1].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).VersionCurrent.String(), "3.1.1")
+}
+
+func TestBuildAtlasDirectoryPackagesProps(t *testing.T) {
+
+	atlas := buildAtlasDirectoryPackagesProps(
+		[]byte(`<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+    <SerilogVersion>3.1.1</SerilogVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="13.0.1" />
+    <PackageVersion Include="Serilog" Version="$(SerilogVersion)" />
+    <PackageVersion Include="xunit" Version="[2.4,3.0)" />
+    <PackageVersion Include="Undefined" Version="$(UndefinedVersion)" />
+  </ItemGroup>
+  <ItemGroup>
+    <GlobalPackageReference Include="Nerdbank.GitVersioning" Version="3.6.133" />
+  </ItemGroup>
+</Project>`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, DOTNET)
+	assert.Equal(t, len(atlas.dependencies), 5)
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrent.String(), "3.1.1")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Nil(t, atlas.dependencies[3].(*Dependency).VersionCurrent)
+	assert.False(t, atlas.dependencies[3].(*Dependency).Unpinned)
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).Name, "Nerdbank.GitVersioning")
+}
@@ -0,0 +1,291 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	modsemver "golang.org/x/mod/semver"
+)
+
+const (
+	proxyUrlGoModuleFile = "https://proxy.golang.org/%s/@v/%s.mod"
+	maxSuccessorProbes   = 20
+)
+
+type GoModuleInfo struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+func (d *Dependency) queryVersionsGo(policy ReleasePolicy) {
+
+	modulePath := d.Name
+	if d.ReplacementPath != "" {
+		modulePath = d.ReplacementPath
+	}
+
+	versions := queryModuleVersionsGo(modulePath)
+	if !isIncompatibleVersion(d.VersionCurrentLiteral) {
+		// legacy +incompatible tags precede the module adopting go.mod
+		compatibleVersions := []string{}
+		for _, ver := range versions {
+			if !isIncompatibleVersion(ver) {
+				compatibleVersions = append(compatibleVersions, ver)
+			}
+		}
+		versions = compatibleVersions
+	}
+	if policy.QueryLatestCommit {
+		latestCommit := queryLatestCommitGo(escapeModulePath(modulePath))
+		if latestCommit != nil && compareVersions(latestCommit, d.VersionCurrent) > 0 {
+			d.VersionLatestCommit = latestCommit
+		}
+	}
+	latestModFile := queryLatestModuleFileGo(modulePath, versions)
+	if latestModFile != nil {
+		if latestModFile.Module != nil {
+			d.Deprecated = latestModFile.Module.Deprecated
+		}
+		d.Retracted, d.RetractedRationale = findRetraction(latestModFile, d.VersionCurrentLiteral)
+	}
+	d.selectLatestVersions(
+		parseSemanticVersions(versions, d.StrictSemVer),
+		policy,
+		filterExcluded(d.ExcludedVersions),
+		filterRetractedGo(latestModFile),
+		filterReleaseAgeGo(modulePath, policy),
+	)
+
+	for _, successorPath := range getSuccessorModulePaths(modulePath, d.VersionCurrent) {
+		successorVersions := queryModuleVersionsGo(successorPath)
+		if len(successorVersions) == 0 {
+			break
+		}
+
+		successor := Dependency{
+			Name:                  successorPath,
+			StrictSemVer:          d.StrictSemVer,
+			VersionCurrentLiteral: d.VersionCurrentLiteral,
+			VersionCurrent:        d.VersionCurrent,
+		}
+		successor.selectLatestVersions(
+			parseSemanticVersions(successorVersions, d.StrictSemVer),
+			policy,
+			filterRetractedGo(queryLatestModuleFileGo(successorPath, successorVersions)),
+			filterReleaseAgeGo(successorPath, policy),
+		)
+		if successor.VersionLatest != nil {
+			d.VersionLatest = successor.VersionLatest
+			d.VersionLatestPrerelease = successor.VersionLatestPrerelease
+			d.LatestModulePath = successorPath
+		}
+	}
+}
+
+func escapeModulePath(modulePath string) string {
+
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		logrus.Fatal(err.Error())
+		panic(fmt.Errorf("failed to escape module path %s", modulePath))
+	}
+	return escapedPath
+}
+
+func queryModuleVersionsGo(modulePath string) []string {
+
+	statusCode, versionsBytes := queryRegistry(fmt.Sprintf(proxyUrlGoModule, escapeModulePath(modulePath)))
+	if statusCode != http.StatusOK {
+		logrus.Debug(fmt.Sprintf("no versions found for module %s", modulePath))
+		return []string{}
+	}
+	versions := []string{}
+	for _, ver := range strings.Split(
+		strings.TrimSpace(
+			strings.ReplaceAll(string(versionsBytes), "\r\n", "\n"),
+		),
+		"\n",
+	) {
+		// pseudo-versions are not releases even if the proxy lists them
+		if ver = strings.TrimSpace(ver); ver != "" && !module.IsPseudoVersion(ver) {
+			versions = append(versions, ver)
+		}
+	}
+	return versions
+}
+
+func filterReleaseAgeGo(modulePath string, policy ReleasePolicy) versionFilter {
+
+	releaseTimes := map[string]time.Time{}
+	return filterReleaseAge(
+		policy.MinAge,
+		func(version IVersion) time.Time {
+			if _, ok := releaseTimes[version.Original()]; !ok {
+				releaseTimes[version.Original()] = queryReleaseTimeGo(escapeModulePath(modulePath), version.Original())
+			}
+			return releaseTimes[version.Original()]
+		},
+	)
+}
+
+func queryLatestModuleFileGo(modulePath string, versions []string) *modfile.File {
+
+	parsedVersions := parseSemanticVersions(versions, true)
+	stableVersions := []IVersion{}
+	for _, ver := range parsedVersions {
+		if ver.Prerelease() == "" {
+			stableVersions = append(stableVersions, ver)
+		}
+	}
+	latest := findLatestVersion(stableVersions)
+	if latest == nil {
+		latest = findLatestVersion(parsedVersions)
+	}
+	if latest == nil {
+		return nil
+	}
+
+	statusCode, fileBytes := queryRegistry(fmt.Sprintf(proxyUrlGoModuleFile, escapeModulePath(modulePath), latest.Original()))
+	if statusCode != http.StatusOK {
+		return nil
+	}
+	modFile, err := modfile.ParseLax("go.mod", fileBytes, nil)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("failed to parse go.mod of %s@%s: %s", modulePath, latest.Original(), err.Error()))
+		return nil
+	}
+	return modFile
+}
+
+func findRetraction(modFile *modfile.File, version string) (bool, string) {
+
+	if modFile == nil {
+		return false, ""
+	}
+	for _, retract := range modFile.Retract {
+		if modsemver.Compare(retract.Low, version) <= 0 && modsemver.Compare(version, retract.High) <= 0 {
+			return true, retract.Rationale
+		}
+	}
+	return false, ""
+}
+
+func filterExcluded(excludedVersions []string) versionFilter {
+
+	return func(version IVersion) bool {
+		for _, excluded := range excludedVersions {
+			if version.Original() == excluded {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func filterRetractedGo(modFile *modfile.File) versionFilter {
+
+	return func(version IVersion) bool {
+		retracted, _ := findRetraction(modFile, version.Original())
+		return !retracted
+	}
+}
+
+func isIncompatibleVersion(version string) bool {
+
+	return strings.HasSuffix(version, "+incompatible")
+}
+
+func getSuccessorModulePaths(modulePath string, current IVersion) []string {
+
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return []string{}
+	}
+
+	separator, major := "/v", int64(1)
+	if strings.HasPrefix(pathMajor, ".") {
+		separator = ".v"
+	}
+	if pathMajor != "" {
+		parsedMajor, err := strconv.ParseInt(strings.TrimPrefix(pathMajor, separator), 10, 64)
+		if err != nil {
+			return []string{}
+		}
+		major = parsedMajor
+	} else if current != nil && current.Major() > major {
+		major = current.Major()
+	}
+
+	successorPaths := []string{}
+	for idx := int64(1); idx <= maxSuccessorProbes; idx++ {
+		successorPaths = append(successorPaths, fmt.Sprintf("%s%s%d", prefix, separator, major+idx))
+	}
+	return successorPaths
+}
+
+func queryModuleInfoGo(url string) GoModuleInfo {
+
+	var moduleInfo GoModuleInfo
+	_, body := queryRegistry(url)
+	if err := json.Unmarshal(body, &moduleInfo); err != nil {
+		return GoModuleInfo{}
+	}
+	return moduleInfo
+}
+
+func queryReleaseTimeGo(modulePath, version string) time.Time {
+
+	return queryModuleInfoGo(fmt.Sprintf(proxyUrlGoModuleInfo, modulePath, version)).Time
+}
+
+// queryLatestCommitGo resolves the head of the default branch, which the
+// proxy accepts as the HEAD query like go get does. A head on a tagged release
+// is left to the latest version.
+func queryLatestCommitGo(modulePath string) IVersion {
+
+	moduleInfo := queryModuleInfoGo(fmt.Sprintf(proxyUrlGoModuleInfo, modulePath, "HEAD"))
+	if !module.IsPseudoVersion(moduleInfo.Version) {
+		return nil
+	}
+	latestCommit, err := NewSematicVersion(moduleInfo.Version, true)
+	if err != nil {
+		return nil
+	}
+	return latestCommit
+}
+
+func describePseudoVersion(version string) (string, time.Time) {
+
+	base, err := module.PseudoVersionBase(version)
+	if err != nil {
+		return "", time.Time{}
+	}
+	committed, err := module.PseudoVersionTime(version)
+	if err != nil {
+		return base, time.Time{}
+	}
+	return base, committed
+}
+
+func (d *Dependency) IsUntagged() bool {
+
+	return d.VersionCurrent != nil && module.IsPseudoVersion(d.VersionCurrentLiteral)
+}
+
+func (d *Dependency) GetTaggedScope() OutdatedScope {
+
+	if d.VersionCurrent == nil || d.VersionLatest == nil {
+		return UNKNOWN
+	}
+	if compareVersions(d.VersionLatest, d.VersionCurrent) <= 0 {
+		return UP_TO_DATE
+	}
+	return getSemanticScope(d.VersionCurrent, d.VersionLatest)
+}
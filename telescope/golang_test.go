@@ -0,0 +1,181 @@
+package telescope
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/modfile"
+)
+
+func TestDescribePseudoVersion(t *testing.T) {
+
+	params := []struct {
+		name      string
+		version   string
+		base      string
+		committed time.Time
+	}{
+		{name: "untagged module", version: "v0.0.0-20170810143723-de5bf2ad4578", base: "", committed: time.Date(2017, 8, 10, 14, 37, 23, 0, time.UTC)},
+		{name: "after release", version: "v1.1.2-0.20180830191138-d8f796af33cc", base: "v1.1.1", committed: time.Date(2018, 8, 30, 19, 11, 38, 0, time.UTC)},
+		{name: "after pre-release", version: "v1.2.0-rc.1.0.20200101000000-abcdefabcdef", base: "v1.2.0-rc.1", committed: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				base, committed := describePseudoVersion(param.version)
+				assert.Equal(t, base, param.base)
+				assert.Equal(t, committed, param.committed)
+			},
+		)
+	}
+}
+
+func TestGetTaggedScope(t *testing.T) {
+
+	params := []struct {
+		name     string
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{name: "no tag", current: "v0.0.0-20170810143723-de5bf2ad4578", latest: "", expected: UNKNOWN},
+		{name: "major", current: "v0.0.0-20170810143723-de5bf2ad4578", latest: "v1.5.0", expected: MAJOR},
+		{name: "minor", current: "v1.1.2-0.20180830191138-d8f796af33cc", latest: "v1.2.0", expected: MINOR},
+		{name: "ahead of latest tag", current: "v1.1.2-0.20180830191138-d8f796af33cc", latest: "v1.1.1", expected: UP_TO_DATE},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				dep := NewDependency("module", param.current, true).(*Dependency)
+				if param.latest != "" {
+					dep.VersionLatest, _ = NewSematicVersion(param.latest, true)
+				}
+				assert.True(t, dep.IsUntagged())
+				assert.Equal(t, dep.GetOutdatedScope(), UNTAGGED)
+				assert.Equal(t, dep.GetTaggedScope(), param.expected)
+			},
+		)
+	}
+}
+
+func TestSelectLatestVersionsUntagged(t *testing.T) {
+
+	dep := NewDependency("module", "v0.0.0-20170810143723-de5bf2ad4578", true).(*Dependency)
+	dep.selectLatestVersions(parseSemanticVersions([]string{"v1.0.0", "v2.0.0-beta.1"}, true), ReleasePolicy{})
+	assert.Equal(t, dep.VersionLatest.String(), "1.0.0")
+	assert.Equal(t, dep.VersionLatestPrerelease.String(), "2.0.0-beta.1")
+
+	tagged := NewDependency("module", "v1.0.0", true).(*Dependency)
+	assert.False(t, tagged.IsUntagged())
+}
+
+func TestGetSuccessorModulePaths(t *testing.T) {
+
+	params := []struct {
+		name       string
+		modulePath string
+		current    string
+		expected   []string
+	}{
+		{name: "v1 module", modulePath: "github.com/foo/bar", current: "v1.2.0", expected: []string{"github.com/foo/bar/v2", "github.com/foo/bar/v3"}},
+		{name: "v0 module", modulePath: "github.com/foo/bar", current: "v0.2.0", expected: []string{"github.com/foo/bar/v2", "github.com/foo/bar/v3"}},
+		{name: "v2 module", modulePath: "github.com/foo/bar/v2", current: "v2.0.0", expected: []string{"github.com/foo/bar/v3", "github.com/foo/bar/v4"}},
+		{name: "incompatible", modulePath: "github.com/foo/bar", current: "v65.0.0+incompatible", expected: []string{"github.com/foo/bar/v66", "github.com/foo/bar/v67"}},
+		{name: "gopkg.in", modulePath: "gopkg.in/yaml.v2", current: "v2.4.0", expected: []string{"gopkg.in/yaml.v3", "gopkg.in/yaml.v4"}},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewSematicVersion(param.current, true)
+				successorPaths := getSuccessorModulePaths(param.modulePath, current)
+				assert.Len(t, successorPaths, maxSuccessorProbes)
+				assert.Equal(t, successorPaths[:2], param.expected)
+			},
+		)
+	}
+
+	assert.Empty(t, getSuccessorModulePaths("github.com/foo/bar/v1", nil))
+}
+
+func TestIsIncompatibleVersion(t *testing.T) {
+
+	assert.True(t, isIncompatibleVersion("v11.0.0+incompatible"))
+	assert.False(t, isIncompatibleVersion("v0.25.3"))
+}
+
+func TestFindRetraction(t *testing.T) {
+
+	modFile, err := modfile.ParseLax(
+		"go.mod",
+		[]byte(`// Deprecated: use example.com/other instead.
+module example.com/mod
+
+go 1.19
+
+retract v1.0.1 // leaks credentials
+
+retract [v1.1.0, v1.1.5]
+`),
+		nil,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, modFile.Module.Deprecated, "use example.com/other instead.")
+
+	params := []struct {
+		name      string
+		version   string
+		retracted bool
+		rationale string
+	}{
+		{name: "single version", version: "v1.0.1", retracted: true, rationale: "leaks credentials"},
+		{name: "interval", version: "v1.1.3", retracted: true, rationale: ""},
+		{name: "not retracted", version: "v1.2.0", retracted: false, rationale: ""},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				retracted, rationale := findRetraction(modFile, param.version)
+				assert.Equal(t, retracted, param.retracted)
+				assert.Equal(t, rationale, param.rationale)
+			},
+		)
+	}
+
+	versions := parseSemanticVersions([]string{"v1.0.0", "v1.0.1", "v1.1.5"}, true)
+	assert.Equal(t, findLatestVersion(versions, filterRetractedGo(modFile)).String(), "1.0.0")
+	assert.Equal(t, findLatestVersion(versions, filterRetractedGo(nil)).String(), "1.1.5")
+}
+
+func TestGetWarnings(t *testing.T) {
+
+	dep := Dependency{Retracted: true, Deprecated: "use example.com/other instead."}
+	assert.Equal(t, dep.GetWarnings(), []string{"retracted", "deprecated: use example.com/other instead."})
+
+	dep = Dependency{Retracted: true, RetractedRationale: "leaks credentials"}
+	assert.Equal(t, dep.GetWarnings(), []string{"retracted: leaks credentials"})
+	assert.Empty(t, (&Dependency{}).GetWarnings())
+}
+
+func TestFilterExcluded(t *testing.T) {
+
+	versions := parseSemanticVersions([]string{"v1.2.0", "v1.3.0"}, true)
+	assert.Equal(t, findLatestVersion(versions, filterExcluded([]string{"v1.3.0"})).String(), "1.2.0")
+	assert.Equal(t, findLatestVersion(versions, filterExcluded(nil)).String(), "1.3.0")
+}
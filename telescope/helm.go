@@ -0,0 +1,342 @@
+package telescope
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const helmIndexUrl = "%s/index.yaml"
+
+var HelmRepositoryConfig string
+
+var (
+	helmExactVersionPattern = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+	helmClausePattern       = regexp.MustCompile(`(?:[<>=!~^]+\s*)?[^\s,<>=!~^|]+`)
+)
+
+type HelmIndex struct {
+	Entries map[string][]struct {
+		Version    string    `yaml:"version"`
+		AppVersion string    `yaml:"appVersion"`
+		Created    time.Time `yaml:"created"`
+		Deprecated bool      `yaml:"deprecated"`
+	} `yaml:"entries"`
+}
+
+type HelmRepositories struct {
+	Repositories []struct {
+		Name string `yaml:"name"`
+		Url  string `yaml:"url"`
+	} `yaml:"repositories"`
+}
+
+var helmIndexes sync.Map
+
+type helmIndexEntry struct {
+	once  sync.Once
+	index *HelmIndex
+}
+
+type HelmConstraint struct {
+	original    string
+	constraints *semver.Constraints
+}
+
+type ChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+type ChartYaml struct {
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	Dependencies []ChartDependency `yaml:"dependencies"`
+}
+
+type ChartLock struct {
+	Dependencies []ChartDependency `yaml:"dependencies"`
+}
+
+func NewHelmConstraint(constraint string) (*HelmConstraint, error) {
+
+	normalized := constraint
+	if !strings.Contains(constraint, " - ") {
+		alternatives := []string{}
+		for _, alternative := range strings.Split(constraint, "||") {
+			clauses := helmClausePattern.FindAllString(alternative, -1)
+			alternatives = append(alternatives, strings.Join(clauses, ","))
+		}
+		normalized = strings.Join(alternatives, "||")
+	}
+	constraints, err := semver.NewConstraint(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid helm constraint %s", constraint)
+	}
+	return &HelmConstraint{original: strings.TrimSpace(constraint), constraints: constraints}, nil
+}
+
+func (c *HelmConstraint) String() string {
+	return c.original
+}
+
+func (c *HelmConstraint) Admits(version IVersion) bool {
+
+	semanticVersion, ok := version.(*semver.Version)
+	return ok && c.constraints.Check(semanticVersion)
+}
+
+func isHelmExactVersion(version string) bool {
+
+	return helmExactVersionPattern.MatchString(strings.TrimSpace(version))
+}
+
+func (d *Dependency) queryVersionsHelm(policy ReleasePolicy) {
+
+	if strings.HasPrefix(d.PackageIndex, "oci://") {
+		d.queryVersionsHelmOci(policy)
+		return
+	}
+	if !strings.HasPrefix(d.PackageIndex, "https://") && !strings.HasPrefix(d.PackageIndex, "http://") {
+		logrus.Debug(fmt.Sprintf("unknown repository %s of chart %s", d.PackageIndex, d.Name))
+		return
+	}
+
+	index := queryHelmIndex(strings.TrimRight(d.PackageIndex, "/"))
+	if index == nil {
+		return
+	}
+	versions := []IVersion{}
+	releaseTimes := map[string]time.Time{}
+	appVersions := map[string]string{}
+	deprecated := map[string]bool{}
+	for _, entry := range index.Entries[d.Name] {
+		version, err := NewSematicVersion(entry.Version, true)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", entry.Version))
+			continue
+		}
+		versions = append(versions, version)
+		releaseTimes[version.String()] = entry.Created
+		appVersions[version.String()] = entry.AppVersion
+		deprecated[version.String()] = entry.Deprecated
+	}
+
+	releaseAge := filterReleaseAge(
+		policy.MinAge,
+		func(version IVersion) time.Time {
+			return releaseTimes[version.String()]
+		},
+	)
+	d.selectLatestVersions(versions, policy, releaseAge)
+	if d.Constraint != nil {
+		d.VersionConstraintLatest = findLatestVersion(versions, releaseAge, d.Constraint.Admits)
+	}
+
+	// a chart is deprecated by publishing a last version flagged as such
+	if newest := findLatestVersion(versions); newest != nil && deprecated[newest.String()] {
+		d.Deprecated = "chart deprecated"
+	}
+	current := d.VersionCurrent
+	if current == nil {
+		current = d.VersionConstraintLatest
+	}
+	if current != nil && d.VersionLatest != nil {
+		d.AppVersion, d.AppVersionLatest = appVersions[current.String()], appVersions[d.VersionLatest.String()]
+	}
+}
+
+func (d *Dependency) queryVersionsHelmOci(policy ReleasePolicy) {
+
+	if policy.MinAge > 0 {
+		logrus.Warn(fmt.Sprintf("skip --min-age for chart %s: unknown release time", d.Name))
+	}
+
+	reference, err := url.Parse(d.PackageIndex)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("invalid repository %s of chart %s", d.PackageIndex, d.Name))
+		return
+	}
+	registry := ociRegistryUrl(reference.Host)
+	repository := strings.Trim(reference.Path+"/"+d.Name, "/")
+	tags, ok := queryOciTags(registry, repository)
+	if !ok {
+		logrus.Debug(fmt.Sprintf("failed to query tags of %s", repository))
+		return
+	}
+
+	versions := []IVersion{}
+	for _, tag := range tags {
+		version, err := NewSematicVersion(strings.ReplaceAll(tag, "_", "+"), true)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	d.selectLatestVersions(versions, policy)
+	if d.Constraint != nil {
+		d.VersionConstraintLatest = findLatestVersion(versions, d.Constraint.Admits)
+	}
+}
+
+func queryHelmIndex(repository string) *HelmIndex {
+
+	cached, _ := helmIndexes.LoadOrStore(repository, &helmIndexEntry{})
+	entry := cached.(*helmIndexEntry)
+	entry.once.Do(func() {
+		indexUrl := fmt.Sprintf(helmIndexUrl, repository)
+		statusCode, body := queryRegistry(indexUrl)
+		var index HelmIndex
+		if statusCode != http.StatusOK || yaml.Unmarshal(body, &index) != nil {
+			logrus.Debug(fmt.Sprintf("failed to query chart repository index %s", indexUrl))
+			return
+		}
+		entry.index = &index
+	})
+	return entry.index
+}
+
+func ociRegistryUrl(host string) string {
+
+	if registryUrl, err := url.Parse(OciRegistry); host == "docker.io" || (err == nil && registryUrl.Host == host) {
+		return strings.TrimRight(OciRegistry, "/")
+	}
+	if hostname := strings.Split(host, ":")[0]; hostname == "localhost" || hostname == "127.0.0.1" {
+		return "http://" + host
+	}
+	return "https://" + host
+}
+
+func resolveHelmRepository(repository string, repositories *HelmRepositories) string {
+
+	name := strings.TrimPrefix(repository, "@")
+	if name == repository {
+		name = strings.TrimPrefix(repository, "alias:")
+	}
+	if name == repository || repositories == nil {
+		return repository
+	}
+	for _, known := range repositories.Repositories {
+		if known.Name == name {
+			return known.Url
+		}
+	}
+	logrus.Debug(fmt.Sprintf("unknown chart repository %s", repository))
+	return repository
+}
+
+func readHelmRepositories(filePath string) *HelmRepositories {
+
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("no helm repositories found: %s", err.Error()))
+		return nil
+	}
+
+	var repositories HelmRepositories
+	err = yaml.Unmarshal(fileBytes, &repositories)
+	if err != nil {
+		panic(err)
+	}
+	return &repositories
+}
+
+func helmRepositoryConfig() string {
+
+	if HelmRepositoryConfig != "" {
+		return HelmRepositoryConfig
+	}
+	if config := os.Getenv("HELM_REPOSITORY_CONFIG"); config != "" {
+		return config
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "helm", "repositories.yaml")
+}
+
+func buildAtlasChartYaml(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var chart ChartYaml
+	err := yaml.Unmarshal(fileBytes, &chart)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         chart.Name,
+		language:     HELM,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	atlas.appendChartDependencies(chart.Dependencies, ignoredPatterns)
+	return &atlas
+}
+
+func buildAtlasChartLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var chartLock ChartLock
+	err := yaml.Unmarshal(fileBytes, &chartLock)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     HELM,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	atlas.appendChartDependencies(chartLock.Dependencies, ignoredPatterns)
+	return &atlas
+}
+
+func (a *Atlas) appendChartDependencies(dependencies []ChartDependency, ignoredPatterns []*regexp.Regexp) {
+
+	var repositories *HelmRepositories
+	reported := map[ChartDependency]bool{}
+	for _, chart := range dependencies {
+		if reported[chart] || matchRegExpPatterns(ignoredPatterns, chart.Name) {
+			continue
+		}
+		reported[chart] = true
+
+		repository := chart.Repository
+		if strings.HasPrefix(repository, "@") || strings.HasPrefix(repository, "alias:") {
+			if repositories == nil {
+				repositories = readHelmRepositories(helmRepositoryConfig())
+			}
+			repository = resolveHelmRepository(repository, repositories)
+		}
+		switch {
+		case repository == "":
+			a.appendDependency(&Dependency{Name: chart.Name, LocalPath: "charts/" + chart.Name})
+		case strings.HasPrefix(repository, "file://"):
+			a.appendDependency(&Dependency{Name: chart.Name, LocalPath: strings.TrimPrefix(repository, "file://")})
+		default:
+			a.appendDependency(NewHelmDependency(chart.Name, chart.Version, repository))
+		}
+	}
+}
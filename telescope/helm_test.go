@@ -0,0 +1,190 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelmConstraintAdmits(t *testing.T) {
+
+	params := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "~1.2.0", version: "1.2.9", expected: true},
+		{constraint: "~1.2.0", version: "1.3.0", expected: false},
+		{constraint: "^12.1.0", version: "12.9.0", expected: true},
+		{constraint: "12.x.x", version: "13.0.0", expected: false},
+		{constraint: ">= 1.2.0 < 2.0.0", version: "1.9.0", expected: true},
+		{constraint: ">= 1.2.0 < 2.0.0", version: "2.0.0", expected: false},
+		{constraint: ">=1.2.0, <2.0.0 || >=3.0.0", version: "3.1.0", expected: true},
+		{constraint: "1.2.0 - 1.4.0", version: "1.5.0", expected: false},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.constraint+" "+param.version,
+			func(t *testing.T) {
+				t.Parallel()
+				constraint, err := NewHelmConstraint(param.constraint)
+				assert.Nil(t, err)
+				version, err := NewSematicVersion(param.version, true)
+				assert.Nil(t, err)
+				assert.Equal(t, constraint.Admits(version), param.expected)
+			},
+		)
+	}
+}
+
+func TestResolveHelmRepository(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"repositories.yaml": `apiVersion: ""
+repositories:
+- name: bitnami
+  url: https://charts.bitnami.com/bitnami
+`,
+	})
+	repositories := readHelmRepositories(filepath.Join(project, "repositories.yaml"))
+
+	assert.Equal(t, resolveHelmRepository("@bitnami", repositories), "https://charts.bitnami.com/bitnami")
+	assert.Equal(t, resolveHelmRepository("alias:bitnami", repositories), "https://charts.bitnami.com/bitnami")
+	assert.Equal(t, resolveHelmRepository("@unknown", repositories), "@unknown")
+	assert.Equal(t, resolveHelmRepository("https://charts.example.com", repositories), "https://charts.example.com")
+}
+
+func TestQueryVersionsHelm(t *testing.T) {
+
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/charts/index.yaml":
+				w.Write([]byte(strings.ReplaceAll(`apiVersion: v1
+entries:
+  postgresql:
+  - version: 13.2.0
+    appVersion: 16.1.0
+    created: RECENT
+  - version: 12.12.10
+    appVersion: 15.4.0
+    created: "2023-09-20T10:00:00Z"
+  - version: 12.1.0
+    appVersion: 15.1.0
+    created: "2022-11-10T10:00:00Z"
+  legacy:
+  - version: 2.0.0
+    deprecated: true
+    created: "2022-01-01T00:00:00Z"
+  - version: 1.0.0
+    created: "2021-01-01T00:00:00Z"
+`, "RECENT", recent)))
+			case "/v2/charts/redis/tags/list":
+				w.Write([]byte(`{"name": "charts/redis", "tags": ["17.3.0", "18.1.0", "18.2.0_build.1"]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	locked := NewHelmDependency("postgresql", "12.1.0", server.URL+"/charts").(*Dependency)
+	locked.queryVersionsHelm(ReleasePolicy{})
+	assert.Equal(t, locked.VersionLatest.String(), "13.2.0")
+	assert.Equal(t, locked.GetOutdatedScope(), MAJOR)
+	assert.Equal(t, buildAppVersionNote(locked), " (app 15.1.0 => 16.1.0)")
+
+	cooled := NewHelmDependency("postgresql", "12.1.0", server.URL+"/charts").(*Dependency)
+	cooled.queryVersionsHelm(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, cooled.VersionLatest.String(), "12.12.10")
+	assert.Equal(t, cooled.GetOutdatedScope(), MINOR)
+
+	ranged := NewHelmDependency("postgresql", "~12.1.0", server.URL+"/charts/").(*Dependency)
+	ranged.queryVersionsHelm(ReleasePolicy{})
+	assert.Equal(t, ranged.VersionConstraintLatest.String(), "12.1.0")
+	assert.Equal(t, ranged.GetOutdatedScope(), CONSTRAINT_OUTDATED)
+
+	deprecated := NewHelmDependency("legacy", "1.0.0", server.URL+"/charts").(*Dependency)
+	deprecated.queryVersionsHelm(ReleasePolicy{})
+	assert.Equal(t, deprecated.Deprecated, "chart deprecated")
+
+	oci := NewHelmDependency("redis", "17.3.0", "oci://"+strings.TrimPrefix(server.URL, "http://")+"/charts").(*Dependency)
+	oci.queryVersionsHelm(ReleasePolicy{})
+	assert.Equal(t, oci.VersionLatest.String(), "18.2.0+build.1")
+	assert.Equal(t, oci.GetOutdatedScope(), MAJOR)
+
+	cooledOci := NewHelmDependency("redis", "17.3.0", "oci://"+strings.TrimPrefix(server.URL, "http://")+"/charts").(*Dependency)
+	cooledOci.queryVersionsHelm(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, cooledOci.VersionLatest.String(), "18.2.0+build.1")
+}
+
+func TestBuildAtlasChartYaml(t *testing.T) {
+
+	atlas := buildAtlasChartYaml(
+		[]byte(`apiVersion: v2
+name: platform
+version: 1.4.0
+appVersion: "2.3.1"
+dependencies:
+  - name: postgresql
+    version: 12.1.0
+    repository: https://charts.bitnami.com/bitnami
+  - name: redis
+    version: ~17.3.0
+    repository: oci://registry-1.docker.io/bitnamicharts
+  - name: common
+    version: 2.x.x
+    repository: file://../common
+  - name: sidecar
+    version: 0.1.0
+    repository: ""
+  - name: monitoring
+    version: 1.0.0
+    repository: https://charts.example.com
+`),
+		[]*regexp.Regexp{regexp.MustCompile("monitoring")},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.name, "platform")
+	assert.Equal(t, atlas.language, HELM)
+	assert.Equal(t, len(atlas.dependencies), 4)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "12.1.0")
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).PackageIndex, "https://charts.bitnami.com/bitnami")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).Constraint.String(), "~17.3.0")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).LocalPath, "../common")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).LocalPath, "charts/sidecar")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetOutdatedScope(), LOCAL)
+}
+
+func TestBuildAtlasChartLock(t *testing.T) {
+
+	atlas := buildAtlasChartLock(
+		[]byte(`dependencies:
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+  version: 12.1.0
+- name: redis
+  repository: oci://registry-1.docker.io/bitnamicharts
+  version: 17.3.14
+digest: sha256:0a3c1d6b4bf1f4e3b1f8e7a1c2d1f0e9b8a7c6d5e4f3a2b1c0d9e8f7a6b5c4d3
+generated: "2023-01-05T10:00:00.000000+01:00"
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, HELM)
+	assert.Equal(t, len(atlas.dependencies), 2)
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrent.String(), "17.3.14")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).PackageIndex, "oci://registry-1.docker.io/bitnamicharts")
+}
@@ -0,0 +1,641 @@
+package telescope
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMavenRepository = "https://repo.maven.apache.org/maven2"
+	gradlePluginPortal     = "https://plugins.gradle.org/m2"
+	mavenArtifactUrl       = "%s/%s/%s"
+)
+
+var MavenRepository = defaultMavenRepository
+
+var mavenPropertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenQualifierAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+type MavenVersion struct {
+	original string
+	items    mavenList
+}
+
+type (
+	mavenInt    string
+	mavenString string
+	mavenList   []interface{}
+)
+
+type MavenMetadata struct {
+	Versioning struct {
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+type MavenPom struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupId      string  `xml:"groupId"`
+		ArtifactId   string  `xml:"artifactId"`
+		Version      string  `xml:"version"`
+		RelativePath *string `xml:"relativePath"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Dependencies         []MavenDependency `xml:"dependencies>dependency"`
+	DependencyManagement []MavenDependency `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+type MavenDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+type GradleVersionCatalog struct {
+	Versions  map[string]interface{} `toml:"versions"`
+	Libraries map[string]interface{} `toml:"libraries"`
+	Plugins   map[string]interface{} `toml:"plugins"`
+}
+
+func NewMavenVersion(version string) (*MavenVersion, error) {
+
+	normalized := strings.ToLower(strings.TrimSpace(version))
+	if normalized == "" || strings.ContainsAny(normalized, " ,[]()${}") {
+		return nil, fmt.Errorf("invalid maven version string %s", version)
+	}
+	return &MavenVersion{original: version, items: parseMavenItems(normalized)}, nil
+}
+
+func parseMavenItems(version string) mavenList {
+
+	root := &mavenList{}
+	stack := []*mavenList{root}
+	current := root
+	openList := func() {
+		nested := &mavenList{}
+		*current = append(*current, nested)
+		stack, current = append(stack, nested), nested
+	}
+	parseItem := func(digits bool, value string, followedByDigit bool) interface{} {
+		if digits {
+			return mavenInt(strings.TrimLeft(value, "0"))
+		}
+		if followedByDigit && len(value) == 1 {
+			if alias, ok := map[string]string{"a": "alpha", "b": "beta", "m": "milestone"}[value]; ok {
+				value = alias
+			}
+		}
+		if alias, ok := mavenQualifierAliases[value]; ok {
+			value = alias
+		}
+		return mavenString(value)
+	}
+
+	digits, start := false, 0
+	for idx, char := range version {
+		switch {
+		case char == '.' || char == '-':
+			if idx == start {
+				*current = append(*current, mavenInt(""))
+			} else {
+				*current = append(*current, parseItem(digits, version[start:idx], false))
+			}
+			start = idx + 1
+			if char == '-' {
+				openList()
+			}
+		case char >= '0' && char <= '9':
+			if !digits && idx > start {
+				*current = append(*current, parseItem(false, version[start:idx], true))
+				start = idx
+				openList()
+			}
+			digits = true
+		default:
+			if digits && idx > start {
+				*current = append(*current, parseItem(true, version[start:idx], false))
+				start = idx
+				openList()
+			}
+			digits = false
+		}
+	}
+	if len(version) > start {
+		*current = append(*current, parseItem(digits, version[start:], false))
+	}
+
+	for idx := len(stack) - 1; idx >= 0; idx-- {
+		stack[idx].normalize()
+	}
+	return resolveMavenLists(*root)
+}
+
+func (l *mavenList) normalize() {
+
+	for idx := len(*l) - 1; idx >= 0; idx-- {
+		if isMavenNull((*l)[idx]) {
+			*l = append((*l)[:idx], (*l)[idx+1:]...)
+		} else if _, nested := (*l)[idx].(*mavenList); !nested {
+			break
+		}
+	}
+}
+
+func resolveMavenLists(list mavenList) mavenList {
+
+	resolved := mavenList{}
+	for _, item := range list {
+		if nested, ok := item.(*mavenList); ok {
+			item = resolveMavenLists(*nested)
+		}
+		resolved = append(resolved, item)
+	}
+	return resolved
+}
+
+func isMavenNull(item interface{}) bool {
+
+	switch item := item.(type) {
+	case mavenInt:
+		return item == ""
+	case mavenString:
+		return item == ""
+	case *mavenList:
+		return len(*item) == 0
+	case mavenList:
+		return len(item) == 0
+	default:
+		return true
+	}
+}
+
+func comparableQualifier(qualifier string) string {
+
+	for idx, known := range mavenQualifiers {
+		if qualifier == known {
+			return strconv.Itoa(idx)
+		}
+	}
+	return fmt.Sprintf("%d-%s", len(mavenQualifiers), qualifier)
+}
+
+func compareMavenItems(a, b interface{}) int {
+
+	switch a := a.(type) {
+	case nil:
+		return -compareMavenItems(b, nil)
+	case mavenInt:
+		switch b := b.(type) {
+		case nil:
+			return compareMavenInts(a, "")
+		case mavenInt:
+			return compareMavenInts(a, b)
+		default:
+			return 1
+		}
+	case mavenString:
+		switch b := b.(type) {
+		case nil:
+			return strings.Compare(comparableQualifier(string(a)), comparableQualifier(""))
+		case mavenString:
+			return strings.Compare(comparableQualifier(string(a)), comparableQualifier(string(b)))
+		default:
+			return -1
+		}
+	case mavenList:
+		switch b := b.(type) {
+		case nil:
+			if len(a) == 0 {
+				return 0
+			}
+			return compareMavenItems(a[0], nil)
+		case mavenInt:
+			return -1
+		case mavenString:
+			return 1
+		case mavenList:
+			for idx := 0; idx < len(a) || idx < len(b); idx++ {
+				var itemA, itemB interface{}
+				if idx < len(a) {
+					itemA = a[idx]
+				}
+				if idx < len(b) {
+					itemB = b[idx]
+				}
+				if result := compareMavenItems(itemA, itemB); result != 0 {
+					return result
+				}
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+func compareMavenInts(a, b mavenInt) int {
+
+	if len(a) != len(b) {
+		return compareInt64(int64(len(a)), int64(len(b)))
+	}
+	return strings.Compare(string(a), string(b))
+}
+
+func (v *MavenVersion) Original() string {
+	return v.original
+}
+
+func (v *MavenVersion) String() string {
+	return v.original
+}
+
+func (v *MavenVersion) number(idx int) int64 {
+
+	if idx < len(v.items) {
+		if digits, ok := v.items[idx].(mavenInt); ok {
+			number, _ := strconv.ParseInt(string(digits), 10, 64)
+			return number
+		}
+	}
+	return 0
+}
+
+func (v *MavenVersion) Major() int64 {
+	return v.number(0)
+}
+
+func (v *MavenVersion) Minor() int64 {
+	return v.number(1)
+}
+
+func (v *MavenVersion) Patch() int64 {
+	return v.number(2)
+}
+
+func (v *MavenVersion) Prerelease() string {
+
+	var findQualifier func(list mavenList) string
+	findQualifier = func(list mavenList) string {
+		for _, item := range list {
+			switch item := item.(type) {
+			case mavenString:
+				if compareMavenItems(item, nil) < 0 {
+					return string(item)
+				}
+				return ""
+			case mavenList:
+				if qualifier := findQualifier(item); qualifier != "" {
+					return qualifier
+				}
+			}
+		}
+		return ""
+	}
+	return findQualifier(v.items)
+}
+
+func (v *MavenVersion) Compare(other IVersion) int {
+
+	o := other.(*MavenVersion)
+	return compareMavenItems(v.items, o.items)
+}
+
+func (v *MavenVersion) OutdatedScope(latest IVersion) OutdatedScope {
+
+	return getExtendedScope(v, latest)
+}
+
+func (d *Dependency) queryVersionsJava(policy ReleasePolicy) {
+
+	repository := d.PackageIndex
+	if repository == "" {
+		repository = MavenRepository
+	}
+	groupId, artifactId, _ := strings.Cut(d.Name, ":")
+	artifactUrl := fmt.Sprintf(
+		mavenArtifactUrl,
+		strings.TrimRight(repository, "/"),
+		strings.ReplaceAll(groupId, ".", "/"),
+		artifactId,
+	)
+	statusCode, body := queryRegistry(artifactUrl + "/maven-metadata.xml")
+	var metadata MavenMetadata
+	if statusCode != http.StatusOK || xml.Unmarshal(body, &metadata) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query maven metadata of %s", artifactUrl))
+		return
+	}
+
+	versions := []IVersion{}
+	for _, ver := range metadata.Versioning.Versions {
+		mavenVersion, err := NewMavenVersion(ver)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", ver))
+			continue
+		}
+		versions = append(versions, mavenVersion)
+	}
+	// the publication time is taken from the pom of the candidate versions
+	releaseTimes := map[string]time.Time{}
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				if _, ok := releaseTimes[version.Original()]; !ok {
+					releaseTimes[version.Original()] = queryLastModified(
+						fmt.Sprintf("%s/%s/%s-%s.pom", artifactUrl, version.Original(), artifactId, version.Original()),
+					)
+				}
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func isMavenRange(version string) bool {
+
+	return strings.ContainsAny(version, "[]()+,") || strings.HasPrefix(version, "latest.")
+}
+
+func parseMavenPom(fileBytes []byte) *MavenPom {
+
+	var pom MavenPom
+	err := xml.Unmarshal(fileBytes, &pom)
+	if err != nil {
+		panic(err)
+	}
+	return &pom
+}
+
+func readMavenParents(filePath string, pom *MavenPom) []*MavenPom {
+
+	parents := []*MavenPom{}
+	for pom.Parent.ArtifactId != "" && len(parents) < 16 {
+		relativePath := "../pom.xml"
+		if pom.Parent.RelativePath != nil {
+			relativePath = strings.TrimSpace(*pom.Parent.RelativePath)
+		}
+		if relativePath == "" {
+			break
+		}
+		parentPath := filepath.Join(filepath.Dir(filePath), relativePath)
+		if info, err := os.Stat(parentPath); err == nil && info.IsDir() {
+			parentPath = filepath.Join(parentPath, "pom.xml")
+		}
+		fileBytes, err := os.ReadFile(parentPath)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("no parent pom found: %s", err.Error()))
+			break
+		}
+		parent := parseMavenPom(fileBytes)
+		if parent.ArtifactId != pom.Parent.ArtifactId || parent.groupId() != pom.Parent.GroupId {
+			break
+		}
+		parents = append(parents, parent)
+		filePath, pom = parentPath, parent
+	}
+	return parents
+}
+
+func (p *MavenPom) groupId() string {
+
+	if p.GroupId != "" {
+		return p.GroupId
+	}
+	return p.Parent.GroupId
+}
+
+func mavenProperties(poms []*MavenPom) map[string]string {
+
+	properties := map[string]string{}
+	for idx := len(poms) - 1; idx >= 0; idx-- {
+		for _, entry := range poms[idx].Properties.Entries {
+			properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+		}
+	}
+	project := poms[0]
+	version := project.Version
+	if version == "" {
+		version = project.Parent.Version
+	}
+	properties["project.groupId"] = project.groupId()
+	properties["project.artifactId"] = project.ArtifactId
+	properties["project.version"] = version
+	properties["project.parent.groupId"] = project.Parent.GroupId
+	properties["project.parent.artifactId"] = project.Parent.ArtifactId
+	properties["project.parent.version"] = project.Parent.Version
+	return properties
+}
+
+func interpolateMavenProperties(value string, properties map[string]string) string {
+
+	value = strings.TrimSpace(value)
+	for depth := 0; depth < 16 && mavenPropertyPattern.MatchString(value); depth++ {
+		interpolated := mavenPropertyPattern.ReplaceAllStringFunc(
+			value,
+			func(reference string) string {
+				if property, ok := properties[reference[2:len(reference)-1]]; ok {
+					return property
+				}
+				return reference
+			},
+		)
+		if interpolated == value {
+			break
+		}
+		value = interpolated
+	}
+	return value
+}
+
+func (c *GradleVersionCatalog) resolveVersion(declaration interface{}) string {
+
+	switch value := declaration.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		if reference, ok := value["ref"].(string); ok {
+			return c.resolveVersion(c.Versions[reference])
+		}
+		for _, key := range []string{"strictly", "require", "prefer"} {
+			if version, ok := value[key].(string); ok {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+func (c *GradleVersionCatalog) resolveLibrary(declaration interface{}) (string, string) {
+
+	switch value := declaration.(type) {
+	case string:
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) < 3 {
+			return value, ""
+		}
+		return parts[0] + ":" + parts[1], parts[2]
+	case map[string]interface{}:
+		module, _ := value["module"].(string)
+		if module == "" {
+			group, _ := value["group"].(string)
+			name, _ := value["name"].(string)
+			module = group + ":" + name
+		}
+		return module, c.resolveVersion(value["version"])
+	}
+	return "", ""
+}
+
+func (c *GradleVersionCatalog) resolvePlugin(declaration interface{}) (string, string) {
+
+	var id, version string
+	switch value := declaration.(type) {
+	case string:
+		id, version, _ = strings.Cut(value, ":")
+	case map[string]interface{}:
+		id, _ = value["id"].(string)
+		version = c.resolveVersion(value["version"])
+	}
+	return fmt.Sprintf("%s:%s.gradle.plugin", id, id), version
+}
+
+func buildAtlasPomXml(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	pom := parseMavenPom(fileBytes)
+	poms := append([]*MavenPom{pom}, readMavenParents(filePath, pom)...)
+	properties := mavenProperties(poms)
+	coordinates := func(dependency MavenDependency) string {
+		return interpolateMavenProperties(dependency.GroupId, properties) + ":" +
+			interpolateMavenProperties(dependency.ArtifactId, properties)
+	}
+	managedVersions := map[string]string{}
+	for idx := len(poms) - 1; idx >= 0; idx-- {
+		for _, dependency := range poms[idx].DependencyManagement {
+			managedVersions[coordinates(dependency)] = interpolateMavenProperties(dependency.Version, properties)
+		}
+	}
+
+	atlas := Atlas{
+		name:         pom.ArtifactId,
+		language:     JAVA,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	reported := map[string]bool{}
+	appendMavenDependency := func(name, version, scope string) {
+		if reported[name] || matchRegExpPatterns(ignoredPatterns, name) {
+			return
+		}
+		reported[name] = true
+
+		var dep *Dependency
+		if isMavenRange(version) {
+			dep = &Dependency{Name: name, VersionCurrentLiteral: version, Unpinned: true}
+		} else {
+			dep = NewJavaDependency(name, version).(*Dependency)
+		}
+		dep.Groups = []string{mainGroup}
+		if scope == "test" {
+			dep.Groups = []string{devGroup}
+		}
+		atlas.appendDependency(dep)
+	}
+
+	if len(poms) == 1 && pom.Parent.ArtifactId != "" {
+		appendMavenDependency(pom.Parent.GroupId+":"+pom.Parent.ArtifactId, pom.Parent.Version, "")
+	}
+	for _, dependency := range pom.Dependencies {
+		name := coordinates(dependency)
+		version := interpolateMavenProperties(dependency.Version, properties)
+		if version == "" {
+			version = managedVersions[name]
+		}
+		if version == "" {
+			// managed by a parent or a bill of materials out of reach
+			logrus.Debug(fmt.Sprintf("no version found for %s", name))
+			continue
+		}
+		appendMavenDependency(name, version, dependency.Scope)
+	}
+	for _, dependency := range pom.DependencyManagement {
+		appendMavenDependency(coordinates(dependency), managedVersions[coordinates(dependency)], dependency.Scope)
+	}
+	return &atlas
+}
+
+func buildAtlasGradleCatalog(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var catalog GradleVersionCatalog
+	err := toml.Unmarshal(fileBytes, &catalog)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     JAVA,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	reported := map[string]bool{}
+	appendGradleDependency := func(name, version, packageIndex string) {
+		key := name + " " + version
+		if version == "" || reported[key] || matchRegExpPatterns(ignoredPatterns, name) {
+			return
+		}
+		reported[key] = true
+
+		var dep *Dependency
+		if isMavenRange(version) {
+			dep = &Dependency{Name: name, VersionCurrentLiteral: version, Unpinned: true}
+		} else {
+			dep = NewJavaDependency(name, version).(*Dependency)
+		}
+		dep.PackageIndex = packageIndex
+		atlas.appendDependency(dep)
+	}
+
+	for _, alias := range sortedKeys(catalog.Libraries) {
+		module, version := catalog.resolveLibrary(catalog.Libraries[alias])
+		appendGradleDependency(module, version, "")
+	}
+	for _, alias := range sortedKeys(catalog.Plugins) {
+		marker, version := catalog.resolvePlugin(catalog.Plugins[alias])
+		appendGradleDependency(marker, version, gradlePluginPortal)
+	}
+	return &atlas
+}
@@ -0,0 +1,305 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMavenVersionCompare(t *testing.T) {
+
+	params := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0", b: "1.0.0", expected: 0},
+		{a: "1", b: "1-ga", expected: 0},
+		{a: "2.0.0.Final", b: "2.0.0", expected: 0},
+		{a: "1.0a1", b: "1.0-alpha-1", expected: 0},
+		{a: "1.0-cr1", b: "1.0-rc1", expected: 0},
+		{a: "1.0-alpha-1", b: "1.0-rc1", expected: -1},
+		{a: "1.0-rc1", b: "1.0", expected: -1},
+		{a: "1.0-SNAPSHOT", b: "1.0", expected: -1},
+		{a: "1.0-sp1", b: "1.0", expected: 1},
+		{a: "1.10", b: "1.9", expected: 1},
+		{a: "31.1-android", b: "31.1-jre", expected: -1},
+		{a: "31.1-jre", b: "31.1", expected: 1},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.a+" "+param.b,
+			func(t *testing.T) {
+				t.Parallel()
+				a, _ := NewMavenVersion(param.a)
+				b, _ := NewMavenVersion(param.b)
+				assert.Equal(t, a.Compare(b), param.expected)
+				assert.Equal(t, b.Compare(a), -param.expected)
+			},
+		)
+	}
+}
+
+func TestMavenVersionPrerelease(t *testing.T) {
+
+	params := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.0-rc1", expected: "rc"},
+		{version: "1.0-SNAPSHOT", expected: "snapshot"},
+		{version: "5.0.0-M2", expected: "milestone"},
+		{version: "31.1-jre", expected: ""},
+		{version: "1.0.Final", expected: ""},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.version,
+			func(t *testing.T) {
+				t.Parallel()
+				version, _ := NewMavenVersion(param.version)
+				assert.Equal(t, version.Prerelease(), param.expected)
+			},
+		)
+	}
+}
+
+func TestMavenVersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{current: "31.1-jre", latest: "32.0.0-jre", expected: MAJOR},
+		{current: "2.14.2", latest: "2.15.0", expected: MINOR},
+		{current: "1.0", latest: "1.0.0.1", expected: PATCH},
+		{current: "1.0-rc1", latest: "1.0", expected: PATCH},
+		{current: "1.0", latest: "1.0.0", expected: UP_TO_DATE},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.current+" "+param.latest,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewMavenVersion(param.current)
+				latest, _ := NewMavenVersion(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestInterpolateMavenProperties(t *testing.T) {
+
+	properties := map[string]string{
+		"jackson.version": "${jackson.major}.1",
+		"jackson.major":   "2.15",
+		"project.version": "1.0.0",
+	}
+	assert.Equal(t, interpolateMavenProperties("${jackson.version}", properties), "2.15.1")
+	assert.Equal(t, interpolateMavenProperties(" ${project.version} ", properties), "1.0.0")
+	assert.Equal(t, interpolateMavenProperties("${undefined}", properties), "${undefined}")
+}
+
+func TestQueryVersionsJava(t *testing.T) {
+
+	published := time.Now().Add(-48 * time.Hour).UTC().Format(http.TimeFormat)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/com/example/lib/maven-metadata.xml":
+				w.Write([]byte(`<metadata>
+  <groupId>com.example</groupId>
+  <artifactId>lib</artifactId>
+  <versioning>
+    <versions>
+      <version>1.0</version>
+      <version>1.2</version>
+      <version>1.3</version>
+      <version>2.0-rc1</version>
+    </versions>
+  </versioning>
+</metadata>`))
+			case "/com/example/lib/1.3/lib-1.3.pom":
+				w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			case "/com/example/lib/1.2/lib-1.2.pom", "/com/example/lib/2.0-rc1/lib-2.0-rc1.pom":
+				w.Header().Set("Last-Modified", published)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	repository := MavenRepository
+	MavenRepository = server.URL
+	defer func() { MavenRepository = repository }()
+
+	lib := NewJavaDependency("com.example:lib", "1.0").(*Dependency)
+	lib.queryVersionsJava(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, lib.VersionLatest.String(), "1.2")
+	assert.Equal(t, lib.VersionLatestPrerelease.String(), "2.0-rc1")
+	assert.Equal(t, lib.GetOutdatedScope(), MINOR)
+
+	unknown := NewJavaDependency("com.example:missing", "1.0").(*Dependency)
+	unknown.queryVersionsJava(ReleasePolicy{})
+	assert.Nil(t, unknown.VersionLatest)
+}
+
+func TestBuildAtlasPomXml(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"pom.xml": `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0.0</version>
+  <properties>
+    <jackson.version>2.15.2</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`,
+		"app/pom.xml": `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <junit.version>5.9.3</junit.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <version>${junit.version}</version>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>[1.7,2.0)</version>
+    </dependency>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>managed-elsewhere</artifactId>
+    </dependency>
+  </dependencies>
+</project>`,
+	})
+
+	pomPath := filepath.Join(project, "app", "pom.xml")
+	atlas := buildAtlasPomXml(
+		pomPath,
+		parseDependenciesFile(pomPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, JAVA)
+	assert.Equal(t, atlas.name, "app")
+	assert.Equal(t, len(atlas.dependencies), 4)
+	jackson := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, jackson.Name, "com.fasterxml.jackson.core:jackson-databind")
+	assert.Equal(t, jackson.VersionCurrent.String(), "2.15.2")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).Name, "com.example:core")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrentLiteral, "1.0.0")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).Groups, []string{devGroup})
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetOutdatedScope(), UNPINNED)
+
+	parentPath := filepath.Join(project, "pom.xml")
+	parent := buildAtlasPomXml(
+		parentPath,
+		parseDependenciesFile(parentPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+	assert.Equal(t, len(parent.dependencies), 1)
+	assert.Equal(t, parent.dependencies[0].(*Dependency).VersionCurrentLiteral, "2.15.2")
+}
+
+func TestBuildAtlasPomXmlRemoteParent(t *testing.T) {
+
+	atlas := buildAtlasPomXml(
+		filepath.Join(t.TempDir(), "pom.xml"),
+		[]byte(`<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.1.0</version>
+    <relativePath/>
+  </parent>
+  <artifactId>app</artifactId>
+</project>`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, len(atlas.dependencies), 1)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).Name, "org.springframework.boot:spring-boot-starter-parent")
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "3.1.0")
+}
+
+func TestBuildAtlasGradleCatalog(t *testing.T) {
+
+	atlas := buildAtlasGradleCatalog(
+		[]byte(`[versions]
+kotlin = "1.9.0"
+guava = { strictly = "[31.0,32.0[", prefer = "31.1-jre" }
+junit = { require = "5.9.3" }
+
+[libraries]
+commons-lang3 = "org.apache.commons:commons-lang3:3.12.0"
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+junit-bom = { group = "org.junit", name = "junit-bom", version.ref = "junit" }
+junit-jupiter = { module = "org.junit.jupiter:junit-jupiter" }
+kotlin-stdlib = { module = "org.jetbrains.kotlin:kotlin-stdlib", version.ref = "kotlin" }
+
+[plugins]
+kotlin-jvm = { id = "org.jetbrains.kotlin.jvm", version.ref = "kotlin" }
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, JAVA)
+	assert.Equal(t, len(atlas.dependencies), 5)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "3.12.0")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).Name, "org.junit:junit-bom")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).VersionCurrentLiteral, "5.9.3")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).Name, "org.jetbrains.kotlin:kotlin-stdlib")
+	plugin := atlas.dependencies[4].(*Dependency)
+	assert.Equal(t, plugin.Name, "org.jetbrains.kotlin.jvm:org.jetbrains.kotlin.jvm.gradle.plugin")
+	assert.Equal(t, plugin.VersionCurrent.String(), "1.9.0")
+	assert.Equal(t, plugin.PackageIndex, gradlePluginPortal)
+}
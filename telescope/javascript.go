@@ -0,0 +1,651 @@
+package telescope
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultNpmRegistry = "https://registry.npmjs.org"
+	npmPackumentUrl    = "%s/%s"
+)
+
+var NpmRegistry = defaultNpmRegistry
+
+type NpmPackument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]struct {
+		// Deprecated is the deprecation message, old documents use false
+		// rather than leaving it out.
+		Deprecated interface{} `json:"deprecated"`
+	} `json:"versions"`
+	Time map[string]string `json:"time"`
+}
+
+type YarnLockEntry struct {
+	Descriptors  []string
+	Version      string
+	Resolved     string
+	Dependencies map[string]string
+	Berry        bool
+}
+
+type PackageJson struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+type PackageLockPackage struct {
+	PackageJson
+	Version    string      `json:"version"`
+	Resolved   string      `json:"resolved"`
+	Link       bool        `json:"link"`
+	Dev        bool        `json:"dev"`
+	Workspaces interface{} `json:"workspaces"`
+}
+
+type PackageLock struct {
+	Name            string                        `json:"name"`
+	LockfileVersion int                           `json:"lockfileVersion"`
+	Packages        map[string]PackageLockPackage `json:"packages"`
+}
+
+type YarnBerryLockPackage struct {
+	Version      string            `yaml:"version"`
+	Resolution   string            `yaml:"resolution"`
+	Dependencies map[string]string `yaml:"dependencies"`
+}
+
+type PnpmLockImporter struct {
+	Dependencies         map[string]interface{} `yaml:"dependencies"`
+	DevDependencies      map[string]interface{} `yaml:"devDependencies"`
+	OptionalDependencies map[string]interface{} `yaml:"optionalDependencies"`
+}
+
+type PnpmLockPackage struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Resolution struct {
+		Tarball   string `yaml:"tarball"`
+		Repo      string `yaml:"repo"`
+		Directory string `yaml:"directory"`
+	} `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+type PnpmLock struct {
+	LockfileVersion  string `yaml:"lockfileVersion"`
+	PnpmLockImporter `yaml:",inline"`
+	Importers        map[string]PnpmLockImporter `yaml:"importers"`
+	Packages         map[string]PnpmLockPackage  `yaml:"packages"`
+	Snapshots        map[string]PnpmLockPackage  `yaml:"snapshots"`
+}
+
+func (d *Dependency) queryVersionsJavascript(policy ReleasePolicy) {
+
+	url := fmt.Sprintf(npmPackumentUrl, strings.TrimRight(NpmRegistry, "/"), escapeNpmName(d.Name))
+	statusCode, body := queryRegistry(url)
+	var packument NpmPackument
+	if statusCode != http.StatusOK || json.Unmarshal(body, &packument) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query package document %s", url))
+		return
+	}
+
+	versions := []string{}
+	for ver := range packument.Versions {
+		versions = append(versions, ver)
+	}
+	if message, ok := packument.Versions[d.VersionCurrentLiteral].Deprecated.(string); ok && message != "" {
+		d.Deprecated = message
+	}
+	d.selectLatestVersions(
+		parseSemanticVersions(versions, true),
+		policy,
+		filterDistTagLatest(packument.DistTags["latest"], d.VersionCurrent),
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				published, _ := time.Parse(time.RFC3339, packument.Time[version.Original()])
+				return published
+			},
+		),
+	)
+}
+
+func escapeNpmName(name string) string {
+
+	return strings.Replace(name, "/", "%2F", 1)
+}
+
+// filterDistTagLatest rejects the stable versions above the latest dist-tag,
+// unless the current version is already beyond it. Pre-releases are left to
+// the release policy.
+func filterDistTagLatest(tag string, current IVersion) versionFilter {
+
+	latest, err := NewSematicVersion(tag, true)
+	return func(version IVersion) bool {
+		if err != nil || version.Prerelease() != "" {
+			return true
+		}
+		if current != nil && compareVersions(current, latest) > 0 {
+			return true
+		}
+		return compareVersions(version, latest) <= 0
+	}
+}
+
+func (p *PackageJson) declaredGroups() map[string][]string {
+
+	declaredGroups := map[string][]string{}
+	if p == nil {
+		return declaredGroups
+	}
+	for _, dependencies := range []map[string]string{p.Dependencies, p.OptionalDependencies} {
+		declaredGroups[mainGroup] = append(declaredGroups[mainGroup], sortedKeys(dependencies)...)
+	}
+	declaredGroups[devGroup] = sortedKeys(p.DevDependencies)
+	return declaredGroups
+}
+
+func (p *PackageJson) declaredRange(name string) (string, bool) {
+
+	if p == nil {
+		return "", false
+	}
+	for _, dependencies := range []map[string]string{p.Dependencies, p.DevDependencies, p.OptionalDependencies} {
+		if versionRange, ok := dependencies[name]; ok {
+			return versionRange, true
+		}
+	}
+	return "", false
+}
+
+func parseYarnLock(fileBytes []byte) []YarnLockEntry {
+
+	if !bytes.Contains(fileBytes, []byte("__metadata:")) {
+		return parseYarnClassicLock(string(fileBytes))
+	}
+
+	var berryLock map[string]YarnBerryLockPackage
+	err := yaml.Unmarshal(fileBytes, &berryLock)
+	if err != nil {
+		panic(err)
+	}
+	entries := []YarnLockEntry{}
+	for _, key := range sortedKeys(berryLock) {
+		if key == "__metadata" {
+			continue
+		}
+		descriptors := []string{}
+		for _, descriptor := range strings.Split(key, ",") {
+			descriptors = append(descriptors, strings.TrimSpace(descriptor))
+		}
+		entries = append(
+			entries,
+			YarnLockEntry{
+				Descriptors:  descriptors,
+				Version:      berryLock[key].Version,
+				Resolved:     berryLock[key].Resolution,
+				Dependencies: berryLock[key].Dependencies,
+				Berry:        true,
+			},
+		)
+	}
+	return entries
+}
+
+func (p PackageLockPackage) isWorkspace(directory string) bool {
+
+	patterns := p.Workspaces
+	if object, ok := patterns.(map[string]interface{}); ok {
+		patterns = object["packages"]
+	}
+	list, _ := patterns.([]interface{})
+	for _, pattern := range list {
+		pattern, _ := pattern.(string)
+		if matched, _ := path.Match(strings.TrimSuffix(pattern, "/"), directory); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitNpmDescriptor(descriptor string) (string, string) {
+
+	if len(descriptor) < 2 {
+		return descriptor, ""
+	}
+	idx := strings.Index(descriptor[1:], "@")
+	if idx < 0 {
+		return descriptor, ""
+	}
+	return descriptor[:idx+1], descriptor[idx+2:]
+}
+
+func isNpmTarball(resolved string) bool {
+
+	return strings.HasPrefix(resolved, "http") && strings.Contains(resolved, "/-/")
+}
+
+func parseYarnClassicLock(content string) []YarnLockEntry {
+
+	entries := []YarnLockEntry{}
+	var section string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent == 0:
+			descriptors := []string{}
+			for _, descriptor := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				descriptors = append(descriptors, strings.Trim(strings.TrimSpace(descriptor), `"`))
+			}
+			entries = append(entries, YarnLockEntry{Descriptors: descriptors, Dependencies: map[string]string{}})
+			section = ""
+		case len(entries) == 0:
+			continue
+		case indent <= 2 && strings.HasSuffix(trimmed, ":"):
+			section = strings.TrimSuffix(trimmed, ":")
+		case indent <= 2:
+			key, value := splitYarnField(trimmed)
+			entry := &entries[len(entries)-1]
+			switch key {
+			case "version":
+				entry.Version = value
+			case "resolved":
+				entry.Resolved = value
+			}
+			section = ""
+		case section == "dependencies" || section == "optionalDependencies":
+			key, value := splitYarnField(trimmed)
+			entries[len(entries)-1].Dependencies[key] = value
+		}
+	}
+	return entries
+}
+
+func splitYarnField(line string) (string, string) {
+
+	var key, value string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`) + 1
+		key, value = line[1:end], line[end+1:]
+	} else {
+		key, value, _ = strings.Cut(line, " ")
+	}
+	return key, strings.Trim(strings.TrimSpace(value), `"`)
+}
+
+func (e YarnLockEntry) locator() (name, location string, workspace bool) {
+
+	if !e.Berry {
+		name, versionRange := splitNpmDescriptor(e.Descriptors[0])
+		if alias, ok := cutPrefix(versionRange, "npm:"); ok && strings.LastIndex(alias, "@") > 0 {
+			name, _ = splitNpmDescriptor(alias)
+		}
+		if e.Resolved != "" && !isNpmTarball(e.Resolved) {
+			return name, e.Resolved, false
+		}
+		if e.Resolved == "" && strings.Contains(versionRange, ":") && !strings.HasPrefix(versionRange, "npm:") {
+			return name, versionRange, false
+		}
+		return name, "", false
+	}
+
+	name, reference := splitNpmDescriptor(e.Resolved)
+	protocol, _, _ := strings.Cut(reference, ":")
+	switch protocol {
+	case "npm", "patch":
+		return name, "", false
+	case "workspace":
+		return name, reference, true
+	default:
+		return name, reference, false
+	}
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+func parsePnpmPackageKey(key string, legacy bool) (string, string) {
+
+	key = strings.TrimPrefix(key, "/")
+	if legacy {
+		idx := strings.LastIndex(key, "/")
+		if idx < 0 {
+			return "", ""
+		}
+		return key[:idx], stripPnpmPeerSuffix(key[idx+1:])
+	}
+	name, version := splitNpmDescriptor(key)
+	return name, stripPnpmPeerSuffix(version)
+}
+
+func stripPnpmPeerSuffix(version string) string {
+
+	if strings.Contains(version, ":") {
+		return version
+	}
+	if idx := strings.IndexAny(version, "(_"); idx >= 0 {
+		return version[:idx]
+	}
+	return version
+}
+
+func pnpmImporterVersion(declaration interface{}) string {
+
+	switch value := declaration.(type) {
+	case string:
+		return stripPnpmPeerSuffix(value)
+	case map[string]interface{}:
+		version, _ := value["version"].(string)
+		return stripPnpmPeerSuffix(version)
+	default:
+		return ""
+	}
+}
+
+func resolvePnpmLink(importer, version string) (string, bool) {
+
+	target, ok := cutPrefix(version, "link:")
+	if !ok {
+		return "", false
+	}
+	return path.Join(importer, target), true
+}
+
+func buildAtlasPackageLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var packageLock PackageLock
+	err := json.Unmarshal(fileBytes, &packageLock)
+	if err != nil {
+		panic(err)
+	}
+	if packageLock.LockfileVersion < 2 {
+		panic(fmt.Errorf("unsupported package-lock.json version %d, regenerate it with npm 7+", packageLock.LockfileVersion))
+	}
+
+	atlas := Atlas{
+		name:         packageLock.Name,
+		language:     JAVASCRIPT,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	mergedDependencies := map[string]*Dependency{}
+	for _, key := range sortedKeys(packageLock.Packages) {
+		idx := strings.LastIndex(key, "node_modules/")
+		if idx < 0 {
+			continue
+		}
+		pkg := packageLock.Packages[key]
+		installName, parent := key[idx+len("node_modules/"):], strings.TrimSuffix(key[:idx], "/")
+		name := installName
+		if pkg.Name != "" {
+			name = pkg.Name
+		}
+		if matchRegExpPatterns(ignoredPatterns, name) {
+			continue
+		}
+
+		if pkg.Link && packageLock.Packages[""].isWorkspace(pkg.Resolved) {
+			continue
+		}
+
+		var dep *Dependency
+		if pkg.Link || (pkg.Resolved != "" && !isNpmTarball(pkg.Resolved)) {
+			dep = &Dependency{Name: name, VersionCurrentLiteral: pkg.Version, LocalPath: pkg.Resolved}
+		} else {
+			dep = NewJavascriptDependency(name, pkg.Version).(*Dependency)
+		}
+		dep.Indirect = !isPackageLockDirect(packageLock.Packages, parent, installName)
+		dep.Groups = []string{mainGroup}
+		if pkg.Dev {
+			dep.Groups = []string{devGroup}
+		}
+		atlas.appendJavascriptDependency(mergedDependencies, dep)
+	}
+	return &atlas
+}
+
+func isPackageLockDirect(packages map[string]PackageLockPackage, parent, installName string) bool {
+
+	if project, ok := packages[parent]; ok {
+		if _, declared := project.declaredRange(installName); declared {
+			return true
+		}
+	}
+	if parent != "" {
+		return false
+	}
+	for projectPath, project := range packages {
+		if strings.Contains(projectPath, "node_modules/") {
+			continue
+		}
+		_, declared := project.declaredRange(installName)
+		_, shadowed := packages[projectPath+"/node_modules/"+installName]
+		if declared && !shadowed {
+			return true
+		}
+	}
+	return false
+}
+
+func buildAtlasYarnLock(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	packageJson := readPackageJson(filepath.Join(filepath.Dir(filePath), "package.json"))
+	entries := parseYarnLock(fileBytes)
+
+	atlas := Atlas{
+		name:         "",
+		language:     JAVASCRIPT,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	if packageJson != nil {
+		atlas.name = packageJson.Name
+	}
+	directDescriptors := map[string]bool{}
+	declare := func(dependencies map[string]string) {
+		for name, versionRange := range dependencies {
+			directDescriptors[name+"@"+versionRange] = true
+			directDescriptors[name+"@npm:"+versionRange] = true
+		}
+	}
+	if packageJson != nil {
+		declare(packageJson.Dependencies)
+		declare(packageJson.DevDependencies)
+		declare(packageJson.OptionalDependencies)
+	}
+	requirements := map[string][]string{}
+	for _, entry := range entries {
+		name, _, workspace := entry.locator()
+		if workspace {
+			// workspaces of Yarn Berry list the dependencies of their projects
+			declare(entry.Dependencies)
+		}
+		requirements[name] = append(requirements[name], sortedKeys(entry.Dependencies)...)
+	}
+	groups := propagateGroups(packageJson.declaredGroups(), requirements)
+
+	mergedDependencies := map[string]*Dependency{}
+	for _, entry := range entries {
+		name, location, workspace := entry.locator()
+		if workspace || matchRegExpPatterns(ignoredPatterns, name) {
+			continue
+		}
+
+		direct := false
+		for _, descriptor := range entry.Descriptors {
+			direct = direct || directDescriptors[descriptor]
+		}
+		var dep *Dependency
+		if location != "" {
+			dep = &Dependency{Name: name, VersionCurrentLiteral: entry.Version, LocalPath: location}
+		} else {
+			dep = NewJavascriptDependency(name, entry.Version).(*Dependency)
+		}
+		dep.Indirect = !direct
+		dep.Groups = groups[name]
+		atlas.appendJavascriptDependency(mergedDependencies, dep)
+	}
+	return &atlas
+}
+
+func buildAtlasPnpmLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var pnpmLock PnpmLock
+	err := yaml.Unmarshal(fileBytes, &pnpmLock)
+	if err != nil {
+		panic(err)
+	}
+	importers := pnpmLock.Importers
+	if len(importers) == 0 {
+		importers = map[string]PnpmLockImporter{".": pnpmLock.PnpmLockImporter}
+	}
+	major, err := strconv.Atoi(strings.SplitN(pnpmLock.LockfileVersion, ".", 2)[0])
+	legacy := err == nil && major < 6
+
+	atlas := Atlas{
+		name:         "",
+		language:     JAVASCRIPT,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	mergedDependencies := map[string]*Dependency{}
+	declaredGroups := map[string][]string{}
+	directVersions := map[string]bool{}
+	for _, importer := range sortedKeys(importers) {
+		sections := []struct {
+			group        string
+			dependencies map[string]interface{}
+		}{
+			{mainGroup, importers[importer].Dependencies},
+			{mainGroup, importers[importer].OptionalDependencies},
+			{devGroup, importers[importer].DevDependencies},
+		}
+		for _, section := range sections {
+			for _, name := range sortedKeys(section.dependencies) {
+				version := pnpmImporterVersion(section.dependencies[name])
+				target, linked := resolvePnpmLink(importer, version)
+				if _, member := importers[target]; !linked {
+					declaredGroups[section.group] = append(declaredGroups[section.group], name)
+					directVersions[name+" "+version] = true
+				} else if !member && !matchRegExpPatterns(ignoredPatterns, name) {
+					atlas.appendJavascriptDependency(
+						mergedDependencies,
+						&Dependency{Name: name, LocalPath: target, Groups: []string{section.group}},
+					)
+				}
+			}
+		}
+	}
+
+	requirements := map[string][]string{}
+	for _, packages := range []map[string]PnpmLockPackage{pnpmLock.Packages, pnpmLock.Snapshots} {
+		for key, pkg := range packages {
+			name, _ := parsePnpmPackageKey(key, legacy)
+			requirements[name] = append(requirements[name], sortedKeys(pkg.Dependencies)...)
+			requirements[name] = append(requirements[name], sortedKeys(pkg.OptionalDependencies)...)
+		}
+	}
+	groups := propagateGroups(declaredGroups, requirements)
+
+	for _, key := range sortedKeys(pnpmLock.Packages) {
+		pkg := pnpmLock.Packages[key]
+		name, version := parsePnpmPackageKey(key, legacy)
+		if pkg.Name != "" {
+			name, version = pkg.Name, pkg.Version
+		}
+		if name == "" || matchRegExpPatterns(ignoredPatterns, name) {
+			continue
+		}
+
+		var dep *Dependency
+		location := pkg.Resolution.Repo + pkg.Resolution.Directory
+		if pkg.Resolution.Tarball != "" && !isNpmTarball(pkg.Resolution.Tarball) {
+			location = pkg.Resolution.Tarball
+		}
+		if location != "" {
+			dep = &Dependency{Name: name, VersionCurrentLiteral: version, LocalPath: location}
+		} else {
+			dep = NewJavascriptDependency(name, version).(*Dependency)
+		}
+		dep.Indirect = !directVersions[name+" "+version]
+		dep.Groups = groups[name]
+		atlas.appendJavascriptDependency(mergedDependencies, dep)
+	}
+	return &atlas
+}
+
+func (a *Atlas) appendJavascriptDependency(mergedDependencies map[string]*Dependency, dep *Dependency) {
+
+	key := strings.Join([]string{dep.Name, dep.VersionCurrentLiteral, dep.LocalPath}, " ")
+	mergedDep, ok := mergedDependencies[key]
+	if !ok {
+		mergedDependencies[key] = dep
+		a.appendDependency(dep)
+		return
+	}
+	mergedDep.Indirect = mergedDep.Indirect && dep.Indirect
+	for _, group := range dep.Groups {
+		if !mergedDep.InGroups([]string{group}) {
+			mergedDep.Groups = append(mergedDep.Groups, group)
+		}
+	}
+}
+
+func readPackageJson(filePath string) *PackageJson {
+
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("no package.json found: %s", err.Error()))
+		return nil
+	}
+
+	var packageJson PackageJson
+	err = json.Unmarshal(fileBytes, &packageJson)
+	if err != nil {
+		panic(err)
+	}
+	return &packageJson
+}
@@ -0,0 +1,339 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNpmDescriptor(t *testing.T) {
+
+	params := []struct {
+		descriptor    string
+		expectedName  string
+		expectedRange string
+	}{
+		{descriptor: "lodash@^4.17.0", expectedName: "lodash", expectedRange: "^4.17.0"},
+		{descriptor: "@babel/core@npm:^7.0.0", expectedName: "@babel/core", expectedRange: "npm:^7.0.0"},
+		{descriptor: "@types/node", expectedName: "@types/node", expectedRange: ""},
+		{descriptor: "cjs@npm:string-width@^4.2.0", expectedName: "cjs", expectedRange: "npm:string-width@^4.2.0"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.descriptor,
+			func(t *testing.T) {
+				t.Parallel()
+				name, versionRange := splitNpmDescriptor(param.descriptor)
+				assert.Equal(t, name, param.expectedName)
+				assert.Equal(t, versionRange, param.expectedRange)
+			},
+		)
+	}
+}
+
+func TestParsePnpmPackageKey(t *testing.T) {
+
+	params := []struct {
+		key             string
+		legacy          bool
+		expectedName    string
+		expectedVersion string
+	}{
+		{key: "/react/18.2.0", legacy: true, expectedName: "react", expectedVersion: "18.2.0"},
+		{key: "/@babel/core/7.21.0_supports-color@5.5.0", legacy: true, expectedName: "@babel/core", expectedVersion: "7.21.0"},
+		{key: "/@babel/core@7.21.0(supports-color@5.5.0)", expectedName: "@babel/core", expectedVersion: "7.21.0"},
+		{key: "react@18.2.0", expectedName: "react", expectedVersion: "18.2.0"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.key,
+			func(t *testing.T) {
+				t.Parallel()
+				name, version := parsePnpmPackageKey(param.key, param.legacy)
+				assert.Equal(t, name, param.expectedName)
+				assert.Equal(t, version, param.expectedVersion)
+			},
+		)
+	}
+}
+
+func TestParseYarnClassicLock(t *testing.T) {
+
+	entries := parseYarnClassicLock(`# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.12.13"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.12.13.tgz#dcfc826beef65e75c50e21d3837d7d95798dd658"
+  integrity sha512-HV1Cm0Q3ZrpCR93tkWOYiuYIgLxZXZFVG2VgK+MBWjUqZTundupbfx2aXarXuw5Ko5aMcjtJgbSs4vUGBS5v6g==
+  dependencies:
+    "@babel/highlight" "^7.12.13"
+
+js-tokens@^4.0.0:
+  version "4.0.0"
+  resolved "https://registry.yarnpkg.com/js-tokens/-/js-tokens-4.0.0.tgz#19203fb59991df98e3a287050d4647cdeaf32499"
+
+local-lib@file:../local-lib:
+  version "1.0.0"
+`)
+
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].Descriptors, []string{"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4"})
+	assert.Equal(t, entries[0].Version, "7.12.13")
+	assert.Equal(t, entries[0].Dependencies, map[string]string{"@babel/highlight": "^7.12.13"})
+	name, location, _ := entries[1].locator()
+	assert.Equal(t, name, "js-tokens")
+	assert.Equal(t, location, "")
+	name, location, _ = entries[2].locator()
+	assert.Equal(t, name, "local-lib")
+	assert.Equal(t, location, "file:../local-lib")
+}
+
+func TestQueryVersionsJavascript(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.EscapedPath() {
+			case "/@scope%2Fwidget":
+				w.Write([]byte(`{
+	"dist-tags": {"latest": "2.1.0", "next": "3.0.0-beta.1"},
+	"versions": {
+		"1.0.0": {"deprecated": "upgrade to 2.x"},
+		"2.1.0": {"deprecated": false},
+		"2.2.0": {},
+		"3.0.0-beta.1": {}
+	},
+	"time": {
+		"created": "2020-01-01T00:00:00.000Z",
+		"1.0.0": "2020-01-01T00:00:00.000Z",
+		"2.1.0": "2021-01-01T00:00:00.000Z",
+		"2.2.0": "2099-01-01T00:00:00.000Z",
+		"3.0.0-beta.1": "2022-01-01T00:00:00.000Z"
+	}
+}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	registry := NpmRegistry
+	NpmRegistry = server.URL
+	defer func() { NpmRegistry = registry }()
+
+	widget := NewJavascriptDependency("@scope/widget", "1.0.0").(*Dependency)
+	widget.queryVersionsJavascript(ReleasePolicy{})
+	assert.Equal(t, widget.VersionLatest.String(), "2.1.0")
+	assert.Equal(t, widget.VersionLatestPrerelease.String(), "3.0.0-beta.1")
+	assert.Equal(t, widget.Deprecated, "upgrade to 2.x")
+	assert.Equal(t, widget.GetOutdatedScope(), MAJOR)
+
+	ahead := NewJavascriptDependency("@scope/widget", "2.2.0").(*Dependency)
+	ahead.queryVersionsJavascript(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, ahead.VersionLatest.String(), "2.1.0")
+	assert.Equal(t, ahead.Deprecated, "")
+
+	missing := NewJavascriptDependency("missing", "1.0.0").(*Dependency)
+	missing.queryVersionsJavascript(ReleasePolicy{})
+	assert.Nil(t, missing.VersionLatest)
+}
+
+func TestBuildAtlasPackageLock(t *testing.T) {
+
+	atlas := buildAtlasPackageLock(
+		[]byte(`{
+  "name": "webapp",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "webapp",
+      "workspaces": ["packages/*"],
+      "dependencies": {"react": "^18.2.0"},
+      "devDependencies": {"typescript": "^5.0.0"}
+    },
+    "packages/ui": {
+      "name": "@webapp/ui",
+      "dependencies": {"clsx": "^2.0.0"}
+    },
+    "node_modules/@webapp/ui": {"resolved": "packages/ui", "link": true},
+    "node_modules/clsx": {"version": "2.0.0", "resolved": "https://registry.npmjs.org/clsx/-/clsx-2.0.0.tgz"},
+    "node_modules/loose-envify": {"version": "1.4.0", "resolved": "https://registry.npmjs.org/loose-envify/-/loose-envify-1.4.0.tgz"},
+    "node_modules/react": {"version": "18.2.0", "resolved": "https://registry.npmjs.org/react/-/react-18.2.0.tgz"},
+    "node_modules/typescript": {"version": "5.0.4", "dev": true},
+    "node_modules/typescript/node_modules/loose-envify": {"version": "1.4.0", "dev": true},
+    "node_modules/tool": {"version": "1.0.0", "resolved": "git+ssh://git@github.com/foo/tool.git#abc123"},
+    "node_modules/tool-local": {"resolved": "../tool-local", "link": true}
+  }
+}`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.name, "webapp")
+	assert.Equal(t, atlas.language, JAVASCRIPT)
+	assert.Equal(t, len(atlas.dependencies), 6)
+	clsx := atlas.dependencies[0].(*Dependency)
+	assert.False(t, clsx.Indirect)
+	looseEnvify := atlas.dependencies[1].(*Dependency)
+	assert.True(t, looseEnvify.Indirect)
+	assert.Equal(t, looseEnvify.Groups, []string{"main", "dev"})
+	assert.False(t, atlas.dependencies[2].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).LocalPath, "git+ssh://git@github.com/foo/tool.git#abc123")
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).GetOutdatedScope(), LOCAL)
+	typescript := atlas.dependencies[5].(*Dependency)
+	assert.False(t, typescript.Indirect)
+	assert.True(t, typescript.IsDevelopment())
+}
+
+func TestBuildAtlasYarnLock(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"package.json": `{
+  "name": "webapp",
+  "dependencies": {"react": "^18.2.0"},
+  "devDependencies": {"typescript": "^5.0.0"}
+}`,
+		"yarn.lock": `__metadata:
+  version: 6
+  cacheKey: 8
+
+"js-tokens@npm:^3.0.0 || ^4.0.0":
+  version: 4.0.0
+  resolution: "js-tokens@npm:4.0.0"
+  languageName: node
+  linkType: hard
+
+"loose-envify@npm:^1.1.0":
+  version: 1.4.0
+  resolution: "loose-envify@npm:1.4.0"
+  dependencies:
+    js-tokens: ^3.0.0 || ^4.0.0
+  languageName: node
+  linkType: hard
+
+"react@npm:^18.2.0":
+  version: 18.2.0
+  resolution: "react@npm:18.2.0"
+  dependencies:
+    loose-envify: ^1.1.0
+  languageName: node
+  linkType: hard
+
+"typescript@npm:^5.0.0, typescript@patch:typescript@^5.0.0#~builtin<compat/typescript>":
+  version: 5.0.4
+  resolution: "typescript@patch:typescript@npm%3A5.0.4#~builtin<compat/typescript>::version=5.0.4&hash=b5f058"
+  languageName: node
+  linkType: hard
+
+"webapp@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "webapp@workspace:."
+  dependencies:
+    react: ^18.2.0
+    typescript: ^5.0.0
+  languageName: unknown
+  linkType: soft
+`,
+	})
+
+	lockPath := filepath.Join(project, "yarn.lock")
+	atlas := buildAtlasYarnLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.name, "webapp")
+	assert.Equal(t, len(atlas.dependencies), 4)
+	jsTokens := atlas.dependencies[0].(*Dependency)
+	assert.True(t, jsTokens.Indirect)
+	assert.Equal(t, jsTokens.Groups, []string{"main"})
+	assert.Equal(t, jsTokens.VersionCurrent.String(), "4.0.0")
+	react := atlas.dependencies[2].(*Dependency)
+	assert.False(t, react.Indirect)
+	typescript := atlas.dependencies[3].(*Dependency)
+	assert.Equal(t, typescript.Name, "typescript")
+	assert.Equal(t, typescript.VersionCurrent.String(), "5.0.4")
+	assert.False(t, typescript.Indirect)
+	assert.True(t, typescript.IsDevelopment())
+}
+
+func TestBuildAtlasPnpmLock(t *testing.T) {
+
+	atlas := buildAtlasPnpmLock(
+		[]byte(`lockfileVersion: '6.0'
+
+importers:
+  .:
+    dependencies:
+      react:
+        specifier: ^18.2.0
+        version: 18.2.0
+    devDependencies:
+      '@webapp/ui':
+        specifier: workspace:*
+        version: link:packages/ui
+      typescript:
+        specifier: ^5.0.0
+        version: 5.0.4
+  packages/ui:
+    dependencies:
+      local-lib:
+        specifier: file:../../local-lib
+        version: link:../../local-lib
+
+packages:
+  /js-tokens@4.0.0:
+    resolution: {integrity: sha512-RdJUflcE3cUzKiMqQgsCu06FPu9UdIJO0beYbPhHN4k6apgJtifcoCtT9bcxOpYBtpD2kCM6Sbzg4CausW/PKQ==}
+    dev: false
+  /loose-envify@1.4.0:
+    resolution: {integrity: sha512-lyuxPGr/Wfhrlem2CL/UcnUc1zcqKAImBDzukY7Y5F/yQiNdko6+fRLevlw1HgMySw7f611UIY408EtxRSoK3Q==}
+    dependencies:
+      js-tokens: 4.0.0
+    dev: false
+  /react@18.2.0:
+    resolution: {integrity: sha512-/3IjMdb2L9QbBdWiW5e3P2/npwMBaU9mHCSCUzNln0ZCYbcfTsGbTJrU/kGemdH2IWmB2ioZ+zkxtmq6g09fGQ==}
+    dependencies:
+      loose-envify: 1.4.0
+    dev: false
+  /typescript@5.0.4:
+    resolution: {integrity: sha512-cW9T5W9xY37cc+jfEnaUvX91foxtHkza3Nw3wkoF4sSlKn0MONdkdEndig/qPBWXNkmplh3NzayQzCiHM4/hqw==}
+    dev: true
+  github.com/foo/tool/abc123:
+    resolution: {tarball: https://codeload.github.com/foo/tool/tar.gz/abc123}
+    name: tool
+    version: 1.0.0
+    dev: false
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, len(atlas.dependencies), 6)
+	localLib := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, localLib.LocalPath, "local-lib")
+	assert.False(t, localLib.Indirect)
+	jsTokens := atlas.dependencies[1].(*Dependency)
+	assert.True(t, jsTokens.Indirect)
+	assert.Equal(t, jsTokens.Groups, []string{"main"})
+	react := atlas.dependencies[3].(*Dependency)
+	assert.False(t, react.Indirect)
+	assert.Equal(t, react.VersionCurrent.String(), "18.2.0")
+	typescript := atlas.dependencies[4].(*Dependency)
+	assert.True(t, typescript.IsDevelopment())
+	tool := atlas.dependencies[5].(*Dependency)
+	assert.Equal(t, tool.Name, "tool")
+	assert.Equal(t, tool.GetOutdatedScope(), LOCAL)
+}
@@ -0,0 +1,408 @@
+package telescope
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var pep440Pattern = regexp.MustCompile(
+	`(?i)^\s*v?` +
+		`(?:(?P<epoch>[0-9]+)!)?` +
+		`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+		`(?P<pre>[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+		`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+		`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+		`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?` +
+		`\s*$`,
+)
+
+var pep440PreLabels map[string]string = map[string]string{
+	"a":       "a",
+	"alpha":   "a",
+	"b":       "b",
+	"beta":    "b",
+	"c":       "rc",
+	"rc":      "rc",
+	"pre":     "rc",
+	"preview": "rc",
+}
+
+var pep440PreOrder map[string]int = map[string]int{"a": 0, "b": 1, "rc": 2}
+
+var pep440ClausePattern = regexp.MustCompile(`^\s*(~=|===|==|!=|<=|>=|<|>)\s*(\S+)\s*$`)
+
+type Pep440Version struct {
+	original string
+	epoch    int64
+	release  []int64
+	preLabel string
+	preNum   int64
+	post     int64
+	dev      int64
+	local    []string
+	hasPost  bool
+	hasDev   bool
+}
+
+func NewPep440Version(version string) (*Pep440Version, error) {
+
+	match := pep440Pattern.FindStringSubmatch(version)
+	if match == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version string %s", version)
+	}
+	group := func(name string) string {
+		return match[pep440Pattern.SubexpIndex(name)]
+	}
+
+	var numberErr error
+	number := func(name string) int64 {
+		if group(name) == "" {
+			return 0
+		}
+		value, err := strconv.ParseInt(group(name), 10, 64)
+		if err != nil {
+			numberErr = fmt.Errorf("version number out of range %s", version)
+		}
+		return value
+	}
+
+	pep440Version := &Pep440Version{original: version}
+	pep440Version.epoch = number("epoch")
+	for _, segment := range strings.Split(group("release"), ".") {
+		value, err := strconv.ParseInt(segment, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("version number out of range %s", version)
+		}
+		pep440Version.release = append(pep440Version.release, value)
+	}
+	if group("pre") != "" {
+		pep440Version.preLabel = pep440PreLabels[strings.ToLower(group("pre_l"))]
+		pep440Version.preNum = number("pre_n")
+	}
+	if group("post") != "" {
+		pep440Version.hasPost = true
+		pep440Version.post = number("post_n1") + number("post_n2")
+	}
+	if group("dev") != "" {
+		pep440Version.hasDev = true
+		pep440Version.dev = number("dev_n")
+	}
+	if local := group("local"); local != "" {
+		pep440Version.local = strings.FieldsFunc(
+			strings.ToLower(local),
+			func(r rune) bool {
+				return strings.ContainsRune("-_.", r)
+			},
+		)
+	}
+	if numberErr != nil {
+		return nil, numberErr
+	}
+	return pep440Version, nil
+}
+
+func (v *Pep440Version) Original() string {
+	return v.original
+}
+
+func (v *Pep440Version) String() string {
+
+	var builder strings.Builder
+	if v.epoch != 0 {
+		builder.WriteString(fmt.Sprintf("%d!", v.epoch))
+	}
+	segments := []string{}
+	for _, segment := range v.release {
+		segments = append(segments, strconv.FormatInt(segment, 10))
+	}
+	builder.WriteString(strings.Join(segments, "."))
+	if v.preLabel != "" {
+		builder.WriteString(fmt.Sprintf("%s%d", v.preLabel, v.preNum))
+	}
+	if v.hasPost {
+		builder.WriteString(fmt.Sprintf(".post%d", v.post))
+	}
+	if v.hasDev {
+		builder.WriteString(fmt.Sprintf(".dev%d", v.dev))
+	}
+	if len(v.local) > 0 {
+		builder.WriteString("+" + strings.Join(v.local, "."))
+	}
+	return builder.String()
+}
+
+func (v *Pep440Version) releaseSegment(idx int) int64 {
+
+	if idx < len(v.release) {
+		return v.release[idx]
+	}
+	return 0
+}
+
+func (v *Pep440Version) Major() int64 {
+	return v.releaseSegment(0)
+}
+
+func (v *Pep440Version) Minor() int64 {
+	return v.releaseSegment(1)
+}
+
+func (v *Pep440Version) Patch() int64 {
+	return v.releaseSegment(2)
+}
+
+func (v *Pep440Version) Prerelease() string {
+
+	var prerelease string
+	if v.preLabel != "" {
+		prerelease += fmt.Sprintf("%s%d", v.preLabel, v.preNum)
+	}
+	if v.hasDev {
+		prerelease += fmt.Sprintf(".dev%d", v.dev)
+	}
+	return strings.TrimPrefix(prerelease, ".")
+}
+
+func (v *Pep440Version) Compare(other IVersion) int {
+
+	o := other.(*Pep440Version)
+	if result := compareInt64(v.epoch, o.epoch); result != 0 {
+		return result
+	}
+	for idx := 0; idx < len(v.release) || idx < len(o.release); idx++ {
+		if result := compareInt64(v.releaseSegment(idx), o.releaseSegment(idx)); result != 0 {
+			return result
+		}
+	}
+	if result := compareInt64(v.preRank(), o.preRank()); result != 0 {
+		return result
+	}
+	if v.preLabel != "" && o.preLabel != "" {
+		if result := compareInt64(v.preNum, o.preNum); result != 0 {
+			return result
+		}
+	}
+	if result := compareOptionalInt64(v.hasPost, v.post, o.hasPost, o.post, false); result != 0 {
+		return result
+	}
+	if result := compareOptionalInt64(v.hasDev, v.dev, o.hasDev, o.dev, true); result != 0 {
+		return result
+	}
+	return compareLocalSegments(v.local, o.local)
+}
+
+func (v *Pep440Version) preRank() int64 {
+
+	switch {
+	case v.preLabel != "":
+		return int64(pep440PreOrder[v.preLabel])
+	case v.hasDev && !v.hasPost:
+		return -1
+	default:
+		return int64(len(pep440PreOrder))
+	}
+}
+
+func (v *Pep440Version) OutdatedScope(other IVersion) OutdatedScope {
+
+	latest := other.(*Pep440Version)
+	if v.Compare(latest) >= 0 {
+		return UP_TO_DATE
+	}
+	if latest.epoch != v.epoch {
+		return MAJOR
+	}
+	for idx := 0; idx < len(v.release) || idx < len(latest.release); idx++ {
+		if v.releaseSegment(idx) == latest.releaseSegment(idx) {
+			continue
+		}
+		switch idx {
+		case 0:
+			return MAJOR
+		case 1:
+			return MINOR
+		default:
+			return PATCH
+		}
+	}
+	return PATCH
+}
+
+func compareInt64(a, b int64) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareOptionalInt64(hasA bool, a int64, hasB bool, b int64, absentLast bool) int {
+
+	switch {
+	case hasA && hasB:
+		return compareInt64(a, b)
+	case hasA == hasB:
+		return 0
+	case hasA == absentLast:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func compareLocalSegments(a, b []string) int {
+
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		numA, errA := strconv.ParseInt(a[idx], 10, 64)
+		numB, errB := strconv.ParseInt(b[idx], 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if result := compareInt64(numA, numB); result != 0 {
+				return result
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		default:
+			if result := strings.Compare(a[idx], b[idx]); result != 0 {
+				return result
+			}
+		}
+	}
+	return compareInt64(int64(len(a)), int64(len(b)))
+}
+
+type pep440Clause struct {
+	operator string
+	literal  string
+	version  *Pep440Version
+	wildcard bool
+}
+
+type Pep440Constraint struct {
+	original     string
+	alternatives [][]pep440Clause
+}
+
+func NewPep440Constraint(specifiers string) (*Pep440Constraint, error) {
+
+	clauses := []pep440Clause{}
+	for _, specifier := range strings.Split(specifiers, ",") {
+		if strings.TrimSpace(specifier) == "" {
+			continue
+		}
+		match := pep440ClausePattern.FindStringSubmatch(specifier)
+		if match == nil {
+			return nil, fmt.Errorf("invalid PEP 440 specifier %s", specifiers)
+		}
+		clause, err := newPep440Clause(match[1], match[2])
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	return &Pep440Constraint{
+		original:     strings.TrimSpace(specifiers),
+		alternatives: [][]pep440Clause{clauses},
+	}, nil
+}
+
+func newPep440Clause(operator, version string) (pep440Clause, error) {
+
+	clause := pep440Clause{operator: operator, literal: version}
+	if operator == "===" {
+		return clause, nil
+	}
+	if strings.HasSuffix(version, ".*") && (operator == "==" || operator == "!=") {
+		clause.wildcard, version = true, strings.TrimSuffix(version, ".*")
+	}
+	parsedVersion, err := NewPep440Version(version)
+	if err != nil {
+		return clause, err
+	}
+	if operator == "~=" && len(parsedVersion.release) < 2 {
+		return clause, fmt.Errorf("invalid compatible release clause ~=%s", version)
+	}
+	clause.version = parsedVersion
+	return clause, nil
+}
+
+func (c *Pep440Constraint) String() string {
+	return c.original
+}
+
+func (c *Pep440Constraint) Admits(version IVersion) bool {
+
+	pep440Version, ok := version.(*Pep440Version)
+	if !ok {
+		return false
+	}
+	for _, clauses := range c.alternatives {
+		admitted := true
+		for _, clause := range clauses {
+			admitted = admitted && clause.admits(pep440Version)
+		}
+		if admitted {
+			return true
+		}
+	}
+	return false
+}
+
+func (c pep440Clause) admits(v *Pep440Version) bool {
+
+	switch c.operator {
+	case "===":
+		return strings.EqualFold(v.Original(), c.literal)
+	case "==":
+		return c.matches(v)
+	case "!=":
+		return !c.matches(v)
+	case "~=":
+		prefix := &Pep440Version{epoch: c.version.epoch, release: c.version.release[:len(c.version.release)-1]}
+		return v.Compare(c.version) >= 0 && matchReleasePrefix(v, prefix)
+	case "<":
+		return v.Compare(c.version) < 0
+	case "<=":
+		return v.Compare(c.version) <= 0
+	case ">":
+		return v.Compare(c.version) > 0
+	case ">=":
+		return v.Compare(c.version) >= 0
+	default:
+		return false
+	}
+}
+
+func (c pep440Clause) matches(v *Pep440Version) bool {
+
+	if c.wildcard {
+		return matchReleasePrefix(v, c.version)
+	}
+	if len(c.version.local) == 0 {
+		public := *v
+		public.local = nil
+		return public.Compare(c.version) == 0
+	}
+	return v.Compare(c.version) == 0
+}
+
+func matchReleasePrefix(v, prefix *Pep440Version) bool {
+
+	if v.epoch != prefix.epoch {
+		return false
+	}
+	for idx, segment := range prefix.release {
+		if v.releaseSegment(idx) != segment {
+			return false
+		}
+	}
+	return true
+}
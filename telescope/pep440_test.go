@@ -0,0 +1,159 @@
+package telescope
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPep440Version(t *testing.T) {
+
+	params := []struct {
+		name       string
+		version    string
+		expected   string
+		prerelease string
+	}{
+		{name: "final", version: "2.28.1", expected: "2.28.1"},
+		{name: "leading v", version: "v1.0", expected: "1.0"},
+		{name: "epoch", version: "1!2.0", expected: "1!2.0"},
+		{name: "four segments", version: "2022.12.7.1", expected: "2022.12.7.1"},
+		{name: "post release", version: "1.0.post1", expected: "1.0.post1"},
+		{name: "implicit post release", version: "1.0-1", expected: "1.0.post1"},
+		{name: "dev release", version: "1.0.dev2", expected: "1.0.dev2", prerelease: "dev2"},
+		{name: "local version", version: "1.13.0+CPU", expected: "1.13.0+cpu"},
+		{name: "release candidate", version: "2.0.0rc1", expected: "2.0.0rc1", prerelease: "rc1"},
+		{name: "alternative spelling", version: "1.0-Alpha.2", expected: "1.0a2", prerelease: "a2"},
+		{name: "implicit pre number", version: "1.0b", expected: "1.0b0", prerelease: "b0"},
+		{name: "pre and dev release", version: "1.0rc1.dev3", expected: "1.0rc1.dev3", prerelease: "rc1.dev3"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				version, err := NewPep440Version(param.version)
+				assert.Nil(t, err)
+				assert.Equal(t, version.String(), param.expected)
+				assert.Equal(t, version.Prerelease(), param.prerelease)
+				assert.Equal(t, version.Original(), param.version)
+			},
+		)
+	}
+}
+
+func TestNewPep440VersionError(t *testing.T) {
+
+	for _, version := range []string{"", "abc", "1.0.0~beta", "1..0", "99999999999999999999.0"} {
+		_, err := NewPep440Version(version)
+		assert.NotNil(t, err, version)
+	}
+}
+
+func TestPep440VersionCompare(t *testing.T) {
+
+	ordered := []string{
+		"1.0.dev0",
+		"1.0a1.dev0",
+		"1.0a1",
+		"1.0a2",
+		"1.0b1",
+		"1.0rc1",
+		"1.0",
+		"1.0+abc",
+		"1.0+abc.5",
+		"1.0+5",
+		"1.0.post1.dev0",
+		"1.0.post1",
+		"1.0.1",
+		"1.1",
+		"2022.12.7",
+		"2022.12.7.1",
+		"1!0.1",
+	}
+	for idx := 0; idx < len(ordered)-1; idx++ {
+		lower, _ := NewPep440Version(ordered[idx])
+		upper, _ := NewPep440Version(ordered[idx+1])
+		assert.Equal(t, lower.Compare(upper), -1, "%s < %s", ordered[idx], ordered[idx+1])
+		assert.Equal(t, upper.Compare(lower), 1, "%s > %s", ordered[idx+1], ordered[idx])
+	}
+
+	padded, _ := NewPep440Version("1.0.0")
+	short, _ := NewPep440Version("1.0")
+	assert.Equal(t, padded.Compare(short), 0)
+}
+
+func TestPep440VersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		name     string
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{name: "up to date", current: "1.0.0", latest: "1.0", expected: UP_TO_DATE},
+		{name: "local ahead", current: "1.0+cpu", latest: "1.0", expected: UP_TO_DATE},
+		{name: "epoch", current: "2022.1", latest: "1!1.0", expected: MAJOR},
+		{name: "major", current: "1.9.9", latest: "2.0", expected: MAJOR},
+		{name: "minor", current: "1.0", latest: "1.1", expected: MINOR},
+		{name: "patch", current: "1.0.0", latest: "1.0.1", expected: PATCH},
+		{name: "fourth segment", current: "2022.12.7", latest: "2022.12.7.1", expected: PATCH},
+		{name: "post release", current: "1.0", latest: "1.0.post1", expected: PATCH},
+		{name: "final of pre-release", current: "2.0rc1", latest: "2.0", expected: PATCH},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewPep440Version(param.current)
+				latest, _ := NewPep440Version(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestPep440ConstraintAdmits(t *testing.T) {
+
+	params := []struct {
+		name       string
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{name: "empty", constraint: "", version: "2.31.0", expected: true},
+		{name: "upper bound", constraint: "<2.30", version: "2.31.0", expected: false},
+		{name: "range", constraint: ">=2.0, <3.0", version: "2.31.0", expected: true},
+		{name: "exclusion", constraint: ">=2.0,!=2.31.0", version: "2.31.0", expected: false},
+		{name: "wildcard", constraint: "==2.*", version: "2.31.0", expected: true},
+		{name: "wildcard miss", constraint: "==2.30.*", version: "2.31.0", expected: false},
+		{name: "compatible", constraint: "~=2.28", version: "2.31.0", expected: true},
+		{name: "compatible patch", constraint: "~=2.28.1", version: "2.31.0", expected: false},
+		{name: "exact local", constraint: "==2.31.0", version: "2.31.0+cpu", expected: true},
+		{name: "arbitrary", constraint: "===2.31.0", version: "2.31.0", expected: true},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				constraint, err := NewPep440Constraint(param.constraint)
+				assert.Nil(t, err)
+				version, _ := NewPep440Version(param.version)
+				assert.Equal(t, constraint.Admits(version), param.expected)
+			},
+		)
+	}
+
+	for _, invalid := range []string{">=", "~=2", "=>2.0", ">=2.0 <3.0"} {
+		_, err := NewPep440Constraint(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
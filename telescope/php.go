@@ -0,0 +1,305 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPackagistRepository = "https://repo.packagist.org"
+	packagistMetadataUrl       = "%s/p2/%s.json"
+)
+
+var PackagistRepository = defaultPackagistRepository
+
+var composerVersionPattern = regexp.MustCompile(
+	`(?i)^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\.(\d+))?(?:[._-]?(stable|beta|b|rc|alpha|a|patch|pl|p)((?:[.-]?\d+)*))?$`,
+)
+
+var composerStabilities = []string{"dev", "alpha", "beta", "RC", "stable", "patch"}
+
+var composerStabilityAliases = map[string]string{
+	"a":      "alpha",
+	"alpha":  "alpha",
+	"b":      "beta",
+	"beta":   "beta",
+	"rc":     "RC",
+	"stable": "stable",
+	"p":      "patch",
+	"pl":     "patch",
+	"patch":  "patch",
+}
+
+type ComposerVersion struct {
+	original         string
+	numbers          [4]int64
+	stability        int
+	stabilityNumbers []int64
+}
+
+// PackagistMetadata is the p2 metadata of packages, whose versions are
+// minified, every entry only holding the fields changed since the previous.
+type PackagistMetadata struct {
+	Packages map[string][]struct {
+		Version string `json:"version"`
+		Time    string `json:"time"`
+		// Abandoned is either true or the name of the suggested replacement.
+		Abandoned interface{} `json:"abandoned"`
+	} `json:"packages"`
+}
+
+type ComposerLockPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  struct {
+		Type      string `json:"type"`
+		Url       string `json:"url"`
+		Reference string `json:"reference"`
+	} `json:"source"`
+	Dist struct {
+		Type string `json:"type"`
+		Url  string `json:"url"`
+	} `json:"dist"`
+}
+
+type ComposerLock struct {
+	Packages    []ComposerLockPackage `json:"packages"`
+	PackagesDev []ComposerLockPackage `json:"packages-dev"`
+}
+
+type ComposerJson struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+func NewComposerVersion(version string) (*ComposerVersion, error) {
+
+	match := composerVersionPattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return nil, fmt.Errorf("invalid composer version string %s", version)
+	}
+
+	composerVersion := &ComposerVersion{original: version, stability: composerStability("stable")}
+	for idx := range composerVersion.numbers {
+		composerVersion.numbers[idx], _ = strconv.ParseInt(match[idx+1], 10, 64)
+	}
+	if match[5] != "" {
+		composerVersion.stability = composerStability(composerStabilityAliases[strings.ToLower(match[5])])
+	}
+	for _, digits := range strings.FieldsFunc(match[6], func(r rune) bool { return r == '.' || r == '-' }) {
+		number, _ := strconv.ParseInt(digits, 10, 64)
+		composerVersion.stabilityNumbers = append(composerVersion.stabilityNumbers, number)
+	}
+	return composerVersion, nil
+}
+
+func composerStability(stability string) int {
+
+	for idx, known := range composerStabilities {
+		if stability == known {
+			return idx
+		}
+	}
+	return 0
+}
+
+func (v *ComposerVersion) Original() string {
+	return v.original
+}
+
+func (v *ComposerVersion) String() string {
+	return v.original
+}
+
+func (v *ComposerVersion) Major() int64 {
+	return v.numbers[0]
+}
+
+func (v *ComposerVersion) Minor() int64 {
+	return v.numbers[1]
+}
+
+func (v *ComposerVersion) Patch() int64 {
+	return v.numbers[2]
+}
+
+func (v *ComposerVersion) Prerelease() string {
+
+	if v.stability >= composerStability("stable") {
+		return ""
+	}
+	return composerStabilities[v.stability]
+}
+
+func (v *ComposerVersion) Compare(other IVersion) int {
+
+	o := other.(*ComposerVersion)
+	for idx := range v.numbers {
+		if result := compareInt64(v.numbers[idx], o.numbers[idx]); result != 0 {
+			return result
+		}
+	}
+	if result := compareInt64(int64(v.stability), int64(o.stability)); result != 0 {
+		return result
+	}
+	for idx := 0; idx < len(v.stabilityNumbers) || idx < len(o.stabilityNumbers); idx++ {
+		var a, b int64
+		if idx < len(v.stabilityNumbers) {
+			a = v.stabilityNumbers[idx]
+		}
+		if idx < len(o.stabilityNumbers) {
+			b = o.stabilityNumbers[idx]
+		}
+		if result := compareInt64(a, b); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func (v *ComposerVersion) OutdatedScope(latest IVersion) OutdatedScope {
+
+	return getExtendedScope(v, latest)
+}
+
+func (d *Dependency) queryVersionsPhp(policy ReleasePolicy) {
+
+	name := strings.ToLower(d.Name)
+	url := fmt.Sprintf(packagistMetadataUrl, strings.TrimRight(PackagistRepository, "/"), name)
+	statusCode, body := queryRegistry(url)
+	var metadata PackagistMetadata
+	if statusCode != http.StatusOK || json.Unmarshal(body, &metadata) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query package metadata %s", url))
+		return
+	}
+
+	versions := []IVersion{}
+	releaseTimes := map[string]time.Time{}
+	for idx, entry := range metadata.Packages[name] {
+		switch abandoned := entry.Abandoned.(type) {
+		case bool:
+			if idx == 0 && abandoned {
+				d.Deprecated = "abandoned"
+			}
+		case string:
+			if idx == 0 {
+				d.Deprecated = fmt.Sprintf("abandoned, use %s instead", abandoned)
+			}
+		}
+		composerVersion, err := NewComposerVersion(entry.Version)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", entry.Version))
+			continue
+		}
+		versions = append(versions, composerVersion)
+		releaseTimes[entry.Version], _ = time.Parse(time.RFC3339, entry.Time)
+	}
+
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func isComposerBranch(version string) bool {
+
+	return strings.HasPrefix(version, "dev-") || strings.HasSuffix(version, "-dev")
+}
+
+func readComposerJson(filePath string) *ComposerJson {
+
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("no composer.json found: %s", err.Error()))
+		return nil
+	}
+
+	var composerJson ComposerJson
+	err = json.Unmarshal(fileBytes, &composerJson)
+	if err != nil {
+		panic(err)
+	}
+	return &composerJson
+}
+
+func (c *ComposerJson) declares(name string) bool {
+
+	if c == nil {
+		return false
+	}
+	for _, requirements := range []map[string]string{c.Require, c.RequireDev} {
+		for declared := range requirements {
+			if strings.EqualFold(declared, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func buildAtlasComposerLock(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var composerLock ComposerLock
+	err := json.Unmarshal(fileBytes, &composerLock)
+	if err != nil {
+		panic(err)
+	}
+	composerJson := readComposerJson(filepath.Join(filepath.Dir(filePath), "composer.json"))
+
+	atlas := Atlas{
+		name:         "",
+		language:     PHP,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	if composerJson != nil {
+		atlas.name = composerJson.Name
+	}
+	sections := map[string][]ComposerLockPackage{mainGroup: composerLock.Packages, devGroup: composerLock.PackagesDev}
+	for _, group := range []string{mainGroup, devGroup} {
+		for _, pkg := range sections[group] {
+			if matchRegExpPatterns(ignoredPatterns, pkg.Name) {
+				continue
+			}
+
+			var dep *Dependency
+			switch {
+			case pkg.Dist.Type == "path":
+				dep = &Dependency{Name: pkg.Name, VersionCurrentLiteral: pkg.Version, LocalPath: pkg.Dist.Url}
+			case isComposerBranch(pkg.Version):
+				dep = &Dependency{Name: pkg.Name, VersionCurrentLiteral: pkg.Version, LocalPath: pkg.Source.Url}
+				if pkg.Source.Reference != "" {
+					dep.LocalPath += "#" + pkg.Source.Reference
+				}
+			default:
+				dep = NewPhpDependency(pkg.Name, pkg.Version).(*Dependency)
+			}
+			dep.Indirect = !composerJson.declares(pkg.Name)
+			dep.Groups = []string{group}
+			atlas.appendDependency(dep)
+		}
+	}
+	return &atlas
+}
@@ -0,0 +1,159 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposerVersionCompare(t *testing.T) {
+
+	params := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "v1.2.3", b: "1.2.3", expected: 0},
+		{a: "1.2", b: "1.2.0.0", expected: 0},
+		{a: "1.0.0-RC1", b: "1.0.0", expected: -1},
+		{a: "1.0.0-beta2", b: "1.0.0-RC1", expected: -1},
+		{a: "1.0.0-b2", b: "1.0.0-beta2", expected: 0},
+		{a: "1.0.0-alpha1", b: "1.0.0-alpha2", expected: -1},
+		{a: "1.0.0-patch1", b: "1.0.0", expected: 1},
+		{a: "2.10.0", b: "2.9.1", expected: 1},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.a+" "+param.b,
+			func(t *testing.T) {
+				t.Parallel()
+				a, _ := NewComposerVersion(param.a)
+				b, _ := NewComposerVersion(param.b)
+				assert.Equal(t, a.Compare(b), param.expected)
+			},
+		)
+	}
+}
+
+func TestComposerVersionPrerelease(t *testing.T) {
+
+	params := []struct {
+		version  string
+		expected string
+	}{
+		{version: "3.0.0-RC1", expected: "RC"},
+		{version: "3.0.0-beta.2", expected: "beta"},
+		{version: "3.0.0", expected: ""},
+		{version: "3.0.0-p1", expected: ""},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.version,
+			func(t *testing.T) {
+				t.Parallel()
+				version, _ := NewComposerVersion(param.version)
+				assert.Equal(t, version.Prerelease(), param.expected)
+			},
+		)
+	}
+}
+
+func TestIsComposerBranch(t *testing.T) {
+
+	assert.True(t, isComposerBranch("dev-main"))
+	assert.True(t, isComposerBranch("2.x-dev"))
+	assert.False(t, isComposerBranch("v2.1.0"))
+}
+
+func TestQueryVersionsPhp(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/p2/monolog/monolog.json":
+				w.Write([]byte(`{"packages": {"monolog/monolog": [
+	{"name": "monolog/monolog", "version": "3.5.0-RC1", "time": "2023-10-20T00:00:00+00:00"},
+	{"version": "3.4.0", "time": "2023-06-21T08:46:11+00:00"},
+	{"version": "3.3.1", "time": "2023-02-06T13:46:10+00:00"}
+]}, "minified": "composer/2.0"}`))
+			case "/p2/swiftmailer/swiftmailer.json":
+				w.Write([]byte(`{"packages": {"swiftmailer/swiftmailer": [
+	{"name": "swiftmailer/swiftmailer", "version": "v6.3.0", "time": "2021-10-18T15:26:12+00:00", "abandoned": "symfony/mailer"}
+]}, "minified": "composer/2.0"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	repository := PackagistRepository
+	PackagistRepository = server.URL
+	defer func() { PackagistRepository = repository }()
+
+	monolog := NewPhpDependency("Monolog/Monolog", "3.3.1").(*Dependency)
+	monolog.queryVersionsPhp(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, monolog.VersionLatest.String(), "3.4.0")
+	assert.Equal(t, monolog.VersionLatestPrerelease.String(), "3.5.0-RC1")
+	assert.Equal(t, monolog.GetOutdatedScope(), MINOR)
+	assert.Equal(t, monolog.Deprecated, "")
+
+	swiftmailer := NewPhpDependency("swiftmailer/swiftmailer", "v6.3.0").(*Dependency)
+	swiftmailer.queryVersionsPhp(ReleasePolicy{})
+	assert.Equal(t, swiftmailer.Deprecated, "abandoned, use symfony/mailer instead")
+	assert.Equal(t, swiftmailer.GetOutdatedScope(), UP_TO_DATE)
+}
+
+func TestBuildAtlasComposerLock(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"composer.json": `{
+	"name": "acme/app",
+	"require": {"php": "^8.1", "monolog/monolog": "^3.0", "acme/tools": "dev-main"},
+	"require-dev": {"phpunit/phpunit": "^10.0"}
+}`,
+		"composer.lock": `{
+	"packages": [
+		{
+			"name": "acme/tools",
+			"version": "dev-main",
+			"source": {"type": "git", "url": "https://github.com/acme/tools.git", "reference": "a1b2c3d"}
+		},
+		{"name": "monolog/monolog", "version": "3.4.0"},
+		{"name": "psr/log", "version": "3.0.0"}
+	],
+	"packages-dev": [
+		{"name": "acme/testing", "version": "1.0.0", "dist": {"type": "path", "url": "../testing"}},
+		{"name": "phpunit/phpunit", "version": "10.2.6"}
+	]
+}`,
+	})
+
+	lockPath := filepath.Join(project, "composer.lock")
+	atlas := buildAtlasComposerLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, PHP)
+	assert.Equal(t, atlas.name, "acme/app")
+	assert.Equal(t, len(atlas.dependencies), 5)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).LocalPath, "https://github.com/acme/tools.git#a1b2c3d")
+	assert.False(t, atlas.dependencies[1].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).VersionCurrent.String(), "3.4.0")
+	assert.True(t, atlas.dependencies[2].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).GetOutdatedScope(), LOCAL)
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).Groups, []string{devGroup})
+	assert.False(t, atlas.dependencies[4].(*Dependency).Indirect)
+}
@@ -0,0 +1,723 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	proxyUrlPythonPackage = "https://pypi.org/pypi/%s/json"
+)
+
+const (
+	mainGroup = "main"
+	devGroup  = "dev"
+)
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+var (
+	requirementPattern        = regexp.MustCompile(`^([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+	requirementOptionPattern  = regexp.MustCompile(`^(-[A-Za-z]|--[a-z-]+)=?\s*(.*)$`)
+	requirementCommentPattern = regexp.MustCompile(`(^|\s)#.*$`)
+	requirementHashPattern    = regexp.MustCompile(`(^|\s)--hash[=\s]\S+`)
+	requirementUrlPattern     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+	requirementEggPattern     = regexp.MustCompile(`[#&]egg=([^&\s]+)`)
+	pinnedSpecifierPattern    = regexp.MustCompile(`^===?\s*([^\s,*]+)$`)
+	poetryClausePattern       = regexp.MustCompile(`(\^|~=|~|===|==|!=|<=|>=|<|>|=)?\s*([0-9*][0-9A-Za-z.*!+-]*)`)
+)
+
+type PythonRequirement struct {
+	Name      string
+	Specifier string
+	Version   string
+	Location  string
+}
+
+type PypiReleaseFile struct {
+	UploadTime   time.Time `json:"upload_time_iso_8601"`
+	Yanked       bool      `json:"yanked"`
+	YankedReason string    `json:"yanked_reason"`
+}
+
+type PypiJson struct {
+	Releases map[string][]PypiReleaseFile `json:"releases"`
+}
+
+type SimpleIndexJson struct {
+	Versions []string          `json:"versions"`
+	Files    []SimpleIndexFile `json:"files"`
+}
+
+type SimpleIndexFile struct {
+	Filename   string    `json:"filename"`
+	UploadTime time.Time `json:"upload-time"`
+	// Yanked is either a boolean or the reason of the yank.
+	Yanked interface{} `json:"yanked"`
+}
+
+type UvLockSource struct {
+	Registry  string `toml:"registry"`
+	Git       string `toml:"git"`
+	Url       string `toml:"url"`
+	Path      string `toml:"path"`
+	Directory string `toml:"directory"`
+	Editable  string `toml:"editable"`
+	Virtual   string `toml:"virtual"`
+}
+
+type UvLockDependency struct {
+	Name string `toml:"name"`
+}
+
+type UvLockPackage struct {
+	Name                 string                        `toml:"name"`
+	Version              string                        `toml:"version"`
+	Source               UvLockSource                  `toml:"source"`
+	Dependencies         []UvLockDependency            `toml:"dependencies"`
+	OptionalDependencies map[string][]UvLockDependency `toml:"optional-dependencies"`
+	DevDependencies      map[string][]UvLockDependency `toml:"dev-dependencies"`
+}
+
+type UvLock struct {
+	Packages []UvLockPackage `toml:"package"`
+}
+
+type PdmLockPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Groups       []string `toml:"groups"`
+	Dependencies []string `toml:"dependencies"`
+	Git          string   `toml:"git"`
+	Path         string   `toml:"path"`
+	Url          string   `toml:"url"`
+}
+
+type PdmLock struct {
+	Packages []PdmLockPackage `toml:"package"`
+}
+
+func (d *Dependency) queryVersionsPython(policy ReleasePolicy) {
+
+	pypiJson, ok := d.queryReleasesPython()
+	if !ok {
+		return
+	}
+
+	releaseTimes := pypiJson.releaseTimes()
+	yankedReleases := pypiJson.yankedReleases()
+	versions := []string{}
+	for ver := range pypiJson.Releases {
+		versions = append(versions, ver)
+	}
+	parsedVersions := parsePep440Versions(versions)
+	for _, ver := range parsedVersions {
+		reason, yanked := yankedReleases[ver.Original()]
+		if yanked && d.VersionCurrent != nil && compareVersions(ver, d.VersionCurrent) == 0 {
+			d.Yanked, d.YankedReason = true, reason
+		}
+	}
+
+	releaseAge := filterReleaseAge(
+		policy.MinAge,
+		func(version IVersion) time.Time {
+			return releaseTimes[version.Original()]
+		},
+	)
+	d.selectLatestVersions(parsedVersions, policy, filterYanked(yankedReleases), releaseAge)
+	if d.Constraint != nil {
+		d.VersionConstraintLatest = findLatestVersion(
+			parsedVersions,
+			filterYanked(yankedReleases),
+			releaseAge,
+			d.Constraint.Admits,
+		)
+	}
+}
+
+func (d *Dependency) queryReleasesPython() (*PypiJson, bool) {
+
+	if d.PackageIndex != "" {
+		return querySimpleIndex(d.PackageIndex, d.Name)
+	}
+
+	var pypiJson PypiJson
+	_, body := queryRegistry(fmt.Sprintf(proxyUrlPythonPackage, d.Name))
+	err := json.Unmarshal(body, &pypiJson)
+	if err != nil {
+		return nil, false
+	}
+	return &pypiJson, true
+}
+
+func querySimpleIndex(indexUrl, name string) (*PypiJson, bool) {
+
+	var simpleIndexJson SimpleIndexJson
+	statusCode, body := queryRegistryWithHeader(
+		fmt.Sprintf("%s/%s/", strings.TrimRight(indexUrl, "/"), normalizePythonName(name)),
+		http.Header{"Accept": {"application/vnd.pypi.simple.v1+json"}},
+	)
+	if statusCode != http.StatusOK || json.Unmarshal(body, &simpleIndexJson) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query package %s from index %s", name, indexUrl))
+		return nil, false
+	}
+
+	pypiJson := PypiJson{Releases: map[string][]PypiReleaseFile{}}
+	for _, ver := range simpleIndexJson.Versions {
+		pypiJson.Releases[ver] = []PypiReleaseFile{}
+	}
+	for _, file := range simpleIndexJson.Files {
+		ver := simpleIndexFileVersion(file.Filename)
+		if ver == "" {
+			continue
+		}
+		releaseFile := PypiReleaseFile{UploadTime: file.UploadTime}
+		switch yanked := file.Yanked.(type) {
+		case bool:
+			releaseFile.Yanked = yanked
+		case string:
+			releaseFile.Yanked, releaseFile.YankedReason = true, yanked
+		}
+		pypiJson.Releases[ver] = append(pypiJson.Releases[ver], releaseFile)
+	}
+	return &pypiJson, true
+}
+
+func simpleIndexFileVersion(filename string) string {
+
+	if strings.HasSuffix(filename, ".whl") {
+		if parts := strings.Split(filename, "-"); len(parts) >= 5 {
+			return parts[1]
+		}
+		return ""
+	}
+	for _, extension := range []string{".tar.gz", ".tar.bz2", ".tgz", ".zip"} {
+		if strings.HasSuffix(filename, extension) {
+			stem := strings.TrimSuffix(filename, extension)
+			return stem[strings.LastIndex(stem, "-")+1:]
+		}
+	}
+	return ""
+}
+
+func pythonPackageIndex(indexUrl string) string {
+
+	parsedUrl, err := url.Parse(indexUrl)
+	if err != nil || parsedUrl.Host == "pypi.org" || parsedUrl.Host == "pypi.python.org" {
+		return ""
+	}
+	return strings.TrimRight(indexUrl, "/")
+}
+
+func (p *PypiJson) releaseTimes() map[string]time.Time {
+
+	releaseTimes := map[string]time.Time{}
+	for ver, files := range p.Releases {
+		for _, file := range files {
+			if published, ok := releaseTimes[ver]; !ok || file.UploadTime.Before(published) {
+				releaseTimes[ver] = file.UploadTime
+			}
+		}
+	}
+	return releaseTimes
+}
+
+func filterYanked(yankedReleases map[string]string) versionFilter {
+
+	return func(version IVersion) bool {
+		_, yanked := yankedReleases[version.Original()]
+		return !yanked
+	}
+}
+
+func (p *PypiJson) yankedReleases() map[string]string {
+
+	yankedReleases := map[string]string{}
+	for ver, files := range p.Releases {
+		if len(files) == 0 {
+			continue
+		}
+		var reason string
+		yanked := true
+		for _, file := range files {
+			yanked = yanked && file.Yanked
+			if file.YankedReason != "" {
+				reason = file.YankedReason
+			}
+		}
+		if yanked {
+			yankedReleases[ver] = reason
+		}
+	}
+	return yankedReleases
+}
+
+func normalizePythonName(name string) string {
+
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+func resolvePoetryGroups(packages []PoetryLockPackage, pyproject *PyprojectToml) map[string][]string {
+
+	if pyproject == nil {
+		return map[string][]string{}
+	}
+
+	declaredGroups := map[string][]string{}
+	declare := func(group string, dependencies map[string]interface{}) {
+		for name := range dependencies {
+			declaredGroups[group] = append(declaredGroups[group], normalizePythonName(name))
+		}
+	}
+	declare(mainGroup, pyproject.Tool.Poetry.Dependencies)
+	declare(devGroup, pyproject.Tool.Poetry.DevDependencies)
+	for group, declaration := range pyproject.Tool.Poetry.Group {
+		declare(group, declaration.Dependencies)
+	}
+
+	requirements := map[string][]string{}
+	for _, pkg := range packages {
+		for name := range pkg.Dependencies {
+			requirements[normalizePythonName(pkg.Name)] = append(
+				requirements[normalizePythonName(pkg.Name)],
+				normalizePythonName(name),
+			)
+		}
+	}
+	return propagateGroups(declaredGroups, requirements)
+}
+
+func resolveUvGroups(packages []UvLockPackage) map[string][]string {
+
+	declaredGroups := map[string][]string{}
+	requirements := map[string][]string{}
+	for _, pkg := range packages {
+		for _, dependency := range pkg.Dependencies {
+			requirements[normalizePythonName(pkg.Name)] = append(
+				requirements[normalizePythonName(pkg.Name)],
+				normalizePythonName(dependency.Name),
+			)
+		}
+		if pkg.Source.Editable == "" && pkg.Source.Virtual == "" {
+			continue
+		}
+
+		declare := func(group string, dependencies []UvLockDependency) {
+			for _, dependency := range dependencies {
+				declaredGroups[group] = append(declaredGroups[group], normalizePythonName(dependency.Name))
+			}
+		}
+		declare(mainGroup, pkg.Dependencies)
+		for group, dependencies := range pkg.OptionalDependencies {
+			declare(group, dependencies)
+		}
+		for group, dependencies := range pkg.DevDependencies {
+			declare(group, dependencies)
+		}
+	}
+	return propagateGroups(declaredGroups, requirements)
+}
+
+func (s UvLockSource) location() string {
+
+	for _, location := range []string{s.Git, s.Url, s.Path, s.Directory, s.Editable, s.Virtual} {
+		if location != "" {
+			return location
+		}
+	}
+	return ""
+}
+
+func (p PdmLockPackage) location() string {
+
+	for _, location := range []string{p.Git, p.Url, p.Path} {
+		if location != "" {
+			return location
+		}
+	}
+	return ""
+}
+
+func propagateGroups(declaredGroups, requirements map[string][]string) map[string][]string {
+
+	packageGroups := map[string][]string{}
+	groups := []string{}
+	for group := range declaredGroups {
+		groups = append(groups, group)
+	}
+	sort.Slice(
+		groups,
+		func(i, j int) bool {
+			if groups[i] == mainGroup || groups[j] == mainGroup {
+				return groups[i] == mainGroup
+			}
+			return groups[i] < groups[j]
+		},
+	)
+	for _, group := range groups {
+		visited := map[string]bool{}
+		queue := append([]string{}, declaredGroups[group]...)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			packageGroups[name] = append(packageGroups[name], group)
+			queue = append(queue, requirements[name]...)
+		}
+	}
+	return packageGroups
+}
+
+func parseRequirementsFile(
+	filePath string,
+	visited map[string]bool,
+) ([]PythonRequirement, map[string]string, string) {
+
+	requirements, constraints := []PythonRequirement{}, map[string]string{}
+	if visited[filepath.Clean(filePath)] {
+		return requirements, constraints, ""
+	}
+	visited[filepath.Clean(filePath)] = true
+
+	return parseRequirementsContent(string(parseDependenciesFile(filePath)), filepath.Dir(filePath), visited)
+}
+
+func parseRequirementsContent(
+	content string,
+	directory string,
+	visited map[string]bool,
+) ([]PythonRequirement, map[string]string, string) {
+
+	requirements, constraints, index := []PythonRequirement{}, map[string]string{}, ""
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\\\n", " "), "\n") {
+		line = requirementCommentPattern.ReplaceAllString(line, "")
+		line = strings.TrimSpace(requirementHashPattern.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+
+		match := requirementOptionPattern.FindStringSubmatch(line)
+		if match == nil {
+			requirements = append(requirements, parseRequirement(line))
+			continue
+		}
+		option, value := match[1], match[2]
+		switch option {
+		case "-r", "--requirement":
+			included, includedConstraints, includedIndex := parseRequirementsFile(filepath.Join(directory, value), visited)
+			requirements = append(requirements, included...)
+			for name, version := range includedConstraints {
+				constraints[name] = version
+			}
+			if includedIndex != "" {
+				index = includedIndex
+			}
+		case "-c", "--constraint":
+			included, _, _ := parseRequirementsFile(filepath.Join(directory, value), visited)
+			for _, requirement := range included {
+				if requirement.Version != "" {
+					constraints[normalizePythonName(requirement.Name)] = requirement.Version
+				}
+			}
+		case "-e", "--editable":
+			requirements = append(requirements, parseReferenceRequirement(value))
+		case "-i", "--index-url":
+			index = value
+		default:
+			// pip merges the releases of extra indexes with those of the main
+			// one, which alone is checked
+			logrus.Debug(fmt.Sprintf("skip requirements option %s", line))
+		}
+	}
+	return requirements, constraints, index
+}
+
+func parseRequirement(line string) PythonRequirement {
+
+	line, _, _ = strings.Cut(line, ";")
+	line = strings.TrimSpace(line)
+	if requirementUrlPattern.MatchString(line) || strings.HasPrefix(line, ".") || strings.HasPrefix(line, "/") {
+		return parseReferenceRequirement(line)
+	}
+
+	match := requirementPattern.FindStringSubmatch(line)
+	if match == nil {
+		return PythonRequirement{Name: line}
+	}
+	specifier := strings.TrimSpace(match[2])
+	if strings.HasPrefix(specifier, "(") && strings.HasSuffix(specifier, ")") {
+		specifier = strings.TrimSpace(specifier[1 : len(specifier)-1])
+	}
+	requirement := PythonRequirement{Name: match[1], Specifier: specifier}
+	if strings.HasPrefix(requirement.Specifier, "@") {
+		requirement.Location = strings.TrimSpace(strings.TrimPrefix(requirement.Specifier, "@"))
+		return requirement
+	}
+	if pinned := pinnedSpecifierPattern.FindStringSubmatch(requirement.Specifier); pinned != nil {
+		requirement.Version = pinned[1]
+	}
+	return requirement
+}
+
+func parseReferenceRequirement(location string) PythonRequirement {
+
+	name := path.Base(strings.TrimRight(strings.SplitN(location, "#", 2)[0], "/"))
+	if egg := requirementEggPattern.FindStringSubmatch(location); egg != nil {
+		name = egg[1]
+	}
+	return PythonRequirement{Name: name, Location: location}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type pyprojectRequirement struct {
+	PythonRequirement
+	group  string
+	poetry bool
+}
+
+func (p *PyprojectToml) declaredRequirements() []pyprojectRequirement {
+
+	requirements := []pyprojectRequirement{}
+	for _, dependency := range p.Project.Dependencies {
+		requirements = append(requirements, pyprojectRequirement{PythonRequirement: parseRequirement(dependency), group: mainGroup})
+	}
+	for _, extra := range sortedKeys(p.Project.OptionalDependencies) {
+		for _, dependency := range p.Project.OptionalDependencies[extra] {
+			requirements = append(requirements, pyprojectRequirement{PythonRequirement: parseRequirement(dependency), group: extra})
+		}
+	}
+
+	poetryGroups := map[string]map[string]interface{}{}
+	declare := func(group string, dependencies map[string]interface{}) {
+		for name, value := range dependencies {
+			if poetryGroups[group] == nil {
+				poetryGroups[group] = map[string]interface{}{}
+			}
+			poetryGroups[group][name] = value
+		}
+	}
+	declare(mainGroup, p.Tool.Poetry.Dependencies)
+	declare(devGroup, p.Tool.Poetry.DevDependencies)
+	for group, declaration := range p.Tool.Poetry.Group {
+		declare(group, declaration.Dependencies)
+	}
+	for _, group := range sortedKeys(poetryGroups) {
+		for _, name := range sortedKeys(poetryGroups[group]) {
+			if name == "python" {
+				continue
+			}
+			requirement := parsePoetryRequirement(name, poetryGroups[group][name])
+			requirements = append(requirements, pyprojectRequirement{PythonRequirement: requirement, group: group, poetry: true})
+		}
+	}
+	return requirements
+}
+
+func parsePoetryRequirement(name string, declaration interface{}) PythonRequirement {
+
+	requirement := PythonRequirement{Name: name}
+	switch value := declaration.(type) {
+	case string:
+		requirement.Specifier = value
+	case map[string]interface{}:
+		for _, source := range []string{"path", "git", "url"} {
+			if location, ok := value[source].(string); ok {
+				requirement.Location = location
+			}
+		}
+		requirement.Specifier, _ = value["version"].(string)
+	case []interface{}:
+		constraints := []string{}
+		for _, item := range value {
+			if table, ok := item.(map[string]interface{}); ok {
+				if constraint, ok := table["version"].(string); ok {
+					constraints = append(constraints, constraint)
+				}
+			}
+		}
+		requirement.Specifier = strings.Join(constraints, " || ")
+	}
+	return requirement
+}
+
+func NewPoetryConstraint(constraint string) (*Pep440Constraint, error) {
+
+	poetryConstraint := &Pep440Constraint{original: strings.TrimSpace(constraint)}
+	for _, alternative := range strings.Split(constraint, "||") {
+		if strings.Trim(poetryClausePattern.ReplaceAllString(alternative, ""), ", ") != "" {
+			return nil, fmt.Errorf("invalid Poetry constraint %s", constraint)
+		}
+		clauses := []pep440Clause{}
+		for _, match := range poetryClausePattern.FindAllStringSubmatch(alternative, -1) {
+			converted, err := convertPoetryClause(match[1], match[2])
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, converted...)
+		}
+		poetryConstraint.alternatives = append(poetryConstraint.alternatives, clauses)
+	}
+	return poetryConstraint, nil
+}
+
+func convertPoetryClause(operator, version string) ([]pep440Clause, error) {
+
+	switch operator {
+	case "":
+		if version == "*" {
+			return []pep440Clause{}, nil
+		}
+		operator = "=="
+	case "=":
+		operator = "=="
+	case "^", "~":
+		lower, err := NewPep440Version(version)
+		if err != nil {
+			return nil, err
+		}
+		upperIdx := 0
+		if operator == "^" {
+			for upperIdx < len(lower.release)-1 && lower.release[upperIdx] == 0 {
+				upperIdx++
+			}
+		} else if len(lower.release) > 1 {
+			upperIdx = 1
+		}
+		upperRelease := append([]int64{}, lower.release[:upperIdx+1]...)
+		upperRelease[upperIdx]++
+		upper := &Pep440Version{epoch: lower.epoch, release: upperRelease}
+		upper.original = upper.String()
+		return []pep440Clause{
+			{operator: ">=", literal: lower.Original(), version: lower},
+			{operator: "<", literal: upper.Original(), version: upper},
+		}, nil
+	}
+
+	clause, err := newPep440Clause(operator, version)
+	if err != nil {
+		return nil, err
+	}
+	return []pep440Clause{clause}, nil
+}
+
+func buildAtlasUvLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var uvLock UvLock
+	err := toml.Unmarshal(fileBytes, &uvLock)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     PYTHON,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	groups := resolveUvGroups(uvLock.Packages)
+	for _, pkg := range uvLock.Packages {
+		if matchRegExpPatterns(ignoredPatterns, pkg.Name) || pkg.Source.Editable == "." || pkg.Source.Virtual == "." {
+			continue
+		}
+
+		var dep IDependable
+		if location := pkg.Source.location(); location != "" {
+			dep = &Dependency{
+				Name:                  pkg.Name,
+				VersionCurrentLiteral: pkg.Version,
+				LocalPath:             location,
+			}
+		} else {
+			dep = NewPythonDependency(pkg.Name, pkg.Version)
+			dep.(*Dependency).PackageIndex = pythonPackageIndex(pkg.Source.Registry)
+		}
+		dep.(*Dependency).Groups = groups[normalizePythonName(pkg.Name)]
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
+
+func buildAtlasPdmLock(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var pdmLock PdmLock
+	err := toml.Unmarshal(fileBytes, &pdmLock)
+	if err != nil {
+		panic(err)
+	}
+
+	var packageIndex string
+	if pyproject := readPyprojectToml(filepath.Join(filepath.Dir(filePath), "pyproject.toml")); pyproject != nil {
+		for _, source := range pyproject.Tool.Pdm.Source {
+			if source.Name == "pypi" {
+				packageIndex = pythonPackageIndex(source.Url)
+			}
+		}
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     PYTHON,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	for _, pkg := range pdmLock.Packages {
+		if matchRegExpPatterns(ignoredPatterns, pkg.Name) {
+			continue
+		}
+
+		var dep IDependable
+		if location := pkg.location(); location != "" {
+			dep = &Dependency{
+				Name:                  pkg.Name,
+				VersionCurrentLiteral: pkg.Version,
+				LocalPath:             location,
+			}
+		} else {
+			dep = NewPythonDependency(pkg.Name, pkg.Version)
+			dep.(*Dependency).PackageIndex = packageIndex
+		}
+		for _, group := range pkg.Groups {
+			if group == "default" {
+				group = mainGroup
+			}
+			dep.(*Dependency).Groups = append(dep.(*Dependency).Groups, group)
+		}
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
@@ -0,0 +1,289 @@
+package telescope
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPypiReleaseTimes(t *testing.T) {
+
+	var pypiJson PypiJson
+	err := json.Unmarshal(
+		[]byte(`{"releases": {
+			"1.0.0": [
+				{"upload_time_iso_8601": "2022-01-02T00:00:00.000000Z"},
+				{"upload_time_iso_8601": "2022-01-01T00:00:00.000000Z"}
+			],
+			"2.0.0": []
+		}}`),
+		&pypiJson,
+	)
+	assert.Nil(t, err)
+
+	releaseTimes := pypiJson.releaseTimes()
+	assert.Equal(t, releaseTimes["1.0.0"], time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NotContains(t, releaseTimes, "2.0.0")
+}
+
+func TestPypiYankedReleases(t *testing.T) {
+
+	var pypiJson PypiJson
+	err := json.Unmarshal(
+		[]byte(`{"releases": {
+			"1.0.0": [{"yanked": false, "yanked_reason": null}],
+			"1.1.0": [{"yanked": true, "yanked_reason": "broken wheel"}, {"yanked": true, "yanked_reason": null}],
+			"1.2.0": [{"yanked": true, "yanked_reason": null}, {"yanked": false, "yanked_reason": null}],
+			"1.3.0": [{"yanked": true, "yanked_reason": null}],
+			"1.4.0": []
+		}}`),
+		&pypiJson,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, pypiJson.yankedReleases(), map[string]string{"1.1.0": "broken wheel", "1.3.0": ""})
+
+	versions := parsePep440Versions([]string{"1.0.0", "1.1.0", "1.2.0", "1.3.0"})
+	latest := findLatestVersion(versions, filterYanked(pypiJson.yankedReleases()))
+	assert.Equal(t, latest.String(), "1.2.0")
+}
+
+func TestParseRequirementsFile(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"requirements.txt": `# compiled by pip-compile
+--index-url https://pypi.example.com/simple
+--extra-index-url https://pypi.org/simple
+-r base.txt
+-c constraints.txt
+
+requests[security]==2.28.1 \
+    --hash=sha256:0000 \
+    --hash=sha256:1111
+    # via -r requirements.in
+numpy==1.24.0 ; python_version >= "3.9"
+Flask>=2.0,<3.0  # not pinned yet
+Django
+wheel===0.38.4
+-e git+https://github.com/foo/bar.git@main#egg=bar
+local-pkg @ file:///opt/local-pkg
+`,
+		"base.txt":        "-r requirements.txt\nurllib3==1.26.12\n",
+		"constraints.txt": "Django==4.1.3\n",
+	})
+
+	requirements, constraints, index := parseRequirementsFile(filepath.Join(project, "requirements.txt"), map[string]bool{})
+	assert.Equal(
+		t,
+		requirements,
+		[]PythonRequirement{
+			{Name: "urllib3", Specifier: "==1.26.12", Version: "1.26.12"},
+			{Name: "requests", Specifier: "==2.28.1", Version: "2.28.1"},
+			{Name: "numpy", Specifier: "==1.24.0", Version: "1.24.0"},
+			{Name: "Flask", Specifier: ">=2.0,<3.0"},
+			{Name: "Django"},
+			{Name: "wheel", Specifier: "===0.38.4", Version: "0.38.4"},
+			{Name: "bar", Location: "git+https://github.com/foo/bar.git@main#egg=bar"},
+			{Name: "local-pkg", Specifier: "@ file:///opt/local-pkg", Location: "file:///opt/local-pkg"},
+		},
+	)
+	assert.Equal(t, constraints, map[string]string{"django": "4.1.3"})
+	assert.Equal(t, index, "https://pypi.example.com/simple")
+}
+
+func TestNewPoetryConstraint(t *testing.T) {
+
+	params := []struct {
+		name       string
+		constraint string
+		admitted   []string
+		rejected   []string
+	}{
+		{name: "caret", constraint: "^1.2.3", admitted: []string{"1.2.3", "1.9"}, rejected: []string{"1.2.2", "2.0"}},
+		{name: "caret zero", constraint: "^0.2.3", admitted: []string{"0.2.9"}, rejected: []string{"0.3.0"}},
+		{name: "caret zeros", constraint: "^0.0.3", admitted: []string{"0.0.3"}, rejected: []string{"0.0.4"}},
+		{name: "tilde", constraint: "~1.2", admitted: []string{"1.2.9"}, rejected: []string{"1.3"}},
+		{name: "tilde major", constraint: "~1", admitted: []string{"1.9"}, rejected: []string{"2.0"}},
+		{name: "any", constraint: "*", admitted: []string{"0.1", "9.9"}},
+		{name: "bare", constraint: "1.2.3", admitted: []string{"1.2.3"}, rejected: []string{"1.2.4"}},
+		{name: "wildcard", constraint: "1.2.*", admitted: []string{"1.2.4"}, rejected: []string{"1.3"}},
+		{name: "space separated", constraint: ">= 1.2 < 1.5", admitted: []string{"1.4"}, rejected: []string{"1.5"}},
+		{name: "alternatives", constraint: "^1.0 || ^3.0", admitted: []string{"1.5", "3.1"}, rejected: []string{"2.0"}},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				constraint, err := NewPoetryConstraint(param.constraint)
+				assert.Nil(t, err)
+				assert.Equal(t, constraint.String(), param.constraint)
+				for _, version := range param.admitted {
+					parsedVersion, _ := NewPep440Version(version)
+					assert.True(t, constraint.Admits(parsedVersion), version)
+				}
+				for _, version := range param.rejected {
+					parsedVersion, _ := NewPep440Version(version)
+					assert.False(t, constraint.Admits(parsedVersion), version)
+				}
+			},
+		)
+	}
+
+	_, err := NewPoetryConstraint("^foo")
+	assert.NotNil(t, err)
+}
+
+func TestSimpleIndexFileVersion(t *testing.T) {
+
+	assert.Equal(t, simpleIndexFileVersion("requests-2.31.0-py3-none-any.whl"), "2.31.0")
+	assert.Equal(t, simpleIndexFileVersion("requests-2.31.0.tar.gz"), "2.31.0")
+	assert.Equal(t, simpleIndexFileVersion("zope.interface-6.1.zip"), "6.1")
+	assert.Equal(t, simpleIndexFileVersion("requests-2.31.0.exe"), "")
+}
+
+func TestQueryVersionsSimpleIndex(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Path, "/simple/my-package/")
+			assert.Equal(t, r.Header.Get("Accept"), "application/vnd.pypi.simple.v1+json")
+			w.Write([]byte(`{
+	"versions": ["1.0.0", "1.1.0", "1.2.0"],
+	"files": [
+		{"filename": "my_package-1.0.0-py3-none-any.whl", "upload-time": "2022-01-01T00:00:00Z", "yanked": "broken build"},
+		{"filename": "my_package-1.1.0.tar.gz", "upload-time": "2022-02-01T00:00:00Z", "yanked": false},
+		{"filename": "my_package-1.2.0.tar.gz", "upload-time": "2022-03-01T00:00:00Z", "yanked": true}
+	]
+}`))
+		}),
+	)
+	defer server.Close()
+
+	dep := NewPythonDependency("My_Package", "1.0.0").(*Dependency)
+	dep.PackageIndex = pythonPackageIndex(server.URL + "/simple/")
+	dep.queryVersionsPython(ReleasePolicy{})
+	assert.Equal(t, dep.VersionLatest.String(), "1.1.0")
+	assert.True(t, dep.Yanked)
+	assert.Equal(t, dep.YankedReason, "broken build")
+	assert.Equal(t, pythonPackageIndex("https://pypi.org/simple"), "")
+
+	constrained := NewPythonConstraintDependency("my-package", ">=1.0", false).(*Dependency)
+	assert.NotNil(t, constrained.Constraint)
+	constrained.PackageIndex = pythonPackageIndex(server.URL + "/simple/")
+	constrained.queryVersionsPython(ReleasePolicy{MinAge: time.Since(time.Date(2022, 1, 15, 0, 0, 0, 0, time.UTC))})
+	assert.Nil(t, constrained.VersionConstraintLatest)
+}
+
+func TestBuildAtlasUvLock(t *testing.T) {
+
+	atlas := buildAtlasUvLock(
+		[]byte(`version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "myproject"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [{ name = "requests" }, { name = "plugin" }]
+
+[package.optional-dependencies]
+socks = [{ name = "pysocks" }]
+
+[package.dev-dependencies]
+dev = [{ name = "pytest" }]
+
+[[package]]
+name = "plugin"
+version = "0.1.0"
+source = { editable = "packages/plugin" }
+
+[[package]]
+name = "pysocks"
+version = "1.7.1"
+source = { registry = "https://pypi.org/simple" }
+
+[[package]]
+name = "pytest"
+version = "8.3.3"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [{ name = "urllib3" }]
+
+[[package]]
+name = "requests"
+version = "2.32.3"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [{ name = "urllib3" }]
+
+[[package]]
+name = "urllib3"
+version = "2.2.3"
+source = { registry = "https://pypi.example.com/simple/" }
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	dependencies := map[string]*Dependency{}
+	for _, dep := range atlas.dependencies {
+		dependencies[dep.(*Dependency).Name] = dep.(*Dependency)
+	}
+	assert.Equal(t, len(atlas.dependencies), 5)
+	assert.Equal(t, dependencies["plugin"].LocalPath, "packages/plugin")
+	assert.Equal(t, dependencies["plugin"].Groups, []string{"main"})
+	assert.Equal(t, dependencies["pysocks"].Groups, []string{"socks"})
+	assert.Equal(t, dependencies["pytest"].Groups, []string{"dev"})
+	assert.Equal(t, dependencies["requests"].PackageIndex, "")
+	assert.Equal(t, dependencies["urllib3"].Groups, []string{"main", "dev"})
+	assert.Equal(t, dependencies["urllib3"].PackageIndex, "https://pypi.example.com/simple")
+}
+
+func TestBuildAtlasPdmLock(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"pyproject.toml": "[[tool.pdm.source]]\nname = \"pypi\"\nurl = \"https://mirror.example.com/simple\"\n",
+		"pdm.lock": `[metadata]
+groups = ["default", "dev"]
+lock_version = "4.4"
+
+[[package]]
+name = "requests"
+version = "2.31.0"
+groups = ["default"]
+dependencies = ["urllib3<3,>=1.21.1"]
+
+[[package]]
+name = "pytest"
+version = "7.4.3"
+groups = ["dev"]
+
+[[package]]
+name = "tool"
+version = "0.1.0"
+git = "https://github.com/foo/tool.git"
+groups = ["dev"]
+`,
+	})
+
+	lockPath := filepath.Join(project, "pdm.lock")
+	atlas := buildAtlasPdmLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, len(atlas.dependencies), 3)
+	requests := atlas.dependencies[0].(*Dependency)
+	assert.Equal(t, requests.Groups, []string{"main"})
+	assert.Equal(t, requests.PackageIndex, "https://mirror.example.com/simple")
+	assert.True(t, atlas.dependencies[1].(*Dependency).IsDevelopment())
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).GetOutdatedScope(), LOCAL)
+}
@@ -0,0 +1,304 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRubyGemsHost = "https://rubygems.org"
+	rubyGemsVersionsUrl = "%s/api/v1/versions/%s.json"
+)
+
+var RubyGemsHost = defaultRubyGemsHost
+
+var (
+	gemVersionPattern     = regexp.MustCompile(`^[0-9]+(?:\.[0-9A-Za-z]+)*$`)
+	gemSegmentPattern     = regexp.MustCompile(`[0-9]+|[A-Za-z]+`)
+	gemfileLockSpecRegex  = regexp.MustCompile(`^    (\S+) \(([^)]+)\)$`)
+	gemfileLockFieldRegex = regexp.MustCompile(`^  ([a-z]+): (.*)$`)
+)
+
+type GemVersion struct {
+	original string
+	segments []interface{}
+}
+
+type RubyGemsVersion struct {
+	Number    string    `json:"number"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GemfileLockSource struct {
+	Kind     string
+	Remote   string
+	Revision string
+	Specs    []GemfileLockSpec
+}
+
+type GemfileLockSpec struct {
+	Name     string
+	Version  string
+	Platform string
+}
+
+type GemfileLock struct {
+	Sources      []GemfileLockSource
+	Dependencies []string
+	BundledWith  string
+}
+
+func NewGemVersion(version string) (*GemVersion, error) {
+
+	normalized := strings.TrimSpace(version)
+	if !gemVersionPattern.MatchString(normalized) {
+		return nil, fmt.Errorf("invalid gem version string %s", version)
+	}
+
+	gemVersion := &GemVersion{original: version}
+	for _, segment := range gemSegmentPattern.FindAllString(normalized, -1) {
+		number, err := strconv.ParseInt(segment, 10, 64)
+		if err != nil {
+			gemVersion.segments = append(gemVersion.segments, segment)
+			continue
+		}
+		gemVersion.segments = append(gemVersion.segments, number)
+	}
+	return gemVersion, nil
+}
+
+func (v *GemVersion) Original() string {
+	return v.original
+}
+
+func (v *GemVersion) String() string {
+	return v.original
+}
+
+func (v *GemVersion) number(idx int) int64 {
+
+	if idx < len(v.segments) {
+		if number, ok := v.segments[idx].(int64); ok {
+			return number
+		}
+	}
+	return 0
+}
+
+func (v *GemVersion) Major() int64 {
+	return v.number(0)
+}
+
+func (v *GemVersion) Minor() int64 {
+	return v.number(1)
+}
+
+func (v *GemVersion) Patch() int64 {
+	return v.number(2)
+}
+
+func (v *GemVersion) Prerelease() string {
+
+	for _, segment := range v.segments {
+		if word, ok := segment.(string); ok {
+			return word
+		}
+	}
+	return ""
+}
+
+func (v *GemVersion) segmentAt(idx int) interface{} {
+
+	if idx < len(v.segments) {
+		return v.segments[idx]
+	}
+	return int64(0)
+}
+
+func (v *GemVersion) Compare(other IVersion) int {
+
+	o := other.(*GemVersion)
+	for idx := 0; idx < len(v.segments) || idx < len(o.segments); idx++ {
+		switch a := v.segmentAt(idx).(type) {
+		case int64:
+			b, ok := o.segmentAt(idx).(int64)
+			if !ok {
+				return 1
+			}
+			if result := compareInt64(a, b); result != 0 {
+				return result
+			}
+		case string:
+			b, ok := o.segmentAt(idx).(string)
+			if !ok {
+				return -1
+			}
+			if result := strings.Compare(a, b); result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+func (v *GemVersion) OutdatedScope(latest IVersion) OutdatedScope {
+
+	return getExtendedScope(v, latest)
+}
+
+// queryVersionsRuby fetches the versions of the gem from the versions API,
+// which leaves yanked versions out, so a current version missing from it has
+// been yanked.
+func (d *Dependency) queryVersionsRuby(policy ReleasePolicy) {
+
+	host := d.PackageIndex
+	if host == "" {
+		host = RubyGemsHost
+	}
+	versionsUrl := fmt.Sprintf(rubyGemsVersionsUrl, strings.TrimRight(host, "/"), url.PathEscape(d.Name))
+	statusCode, body := queryRegistry(versionsUrl)
+	var rubyGemsVersions []RubyGemsVersion
+	if statusCode != http.StatusOK || json.Unmarshal(body, &rubyGemsVersions) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query gem versions %s", versionsUrl))
+		return
+	}
+
+	versions := []IVersion{}
+	releaseTimes := map[string]time.Time{}
+	for _, rubyGemsVersion := range rubyGemsVersions {
+		gemVersion, err := NewGemVersion(rubyGemsVersion.Number)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", rubyGemsVersion.Number))
+			continue
+		}
+		published, ok := releaseTimes[rubyGemsVersion.Number]
+		if !ok {
+			// platform specific builds of a version are listed apart
+			versions = append(versions, gemVersion)
+		}
+		if !ok || rubyGemsVersion.CreatedAt.Before(published) {
+			releaseTimes[rubyGemsVersion.Number] = rubyGemsVersion.CreatedAt
+		}
+	}
+	if _, listed := releaseTimes[d.VersionCurrentLiteral]; !listed && len(releaseTimes) > 0 {
+		d.Yanked = true
+	}
+
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func rubyGemsIndex(remote string) string {
+
+	remoteUrl, err := url.Parse(remote)
+	if err != nil || remoteUrl.Host == "rubygems.org" || remoteUrl.Host == "" {
+		return ""
+	}
+	return strings.TrimRight(remote, "/")
+}
+
+func parseGemfileLock(content string) GemfileLock {
+
+	var gemfileLock GemfileLock
+	var section string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section = strings.TrimSpace(line)
+			if section == "GEM" || section == "GIT" || section == "PATH" || section == "PLUGIN SOURCE" {
+				gemfileLock.Sources = append(gemfileLock.Sources, GemfileLockSource{Kind: section})
+			}
+			continue
+		}
+
+		switch section {
+		case "GEM", "GIT", "PATH", "PLUGIN SOURCE":
+			source := &gemfileLock.Sources[len(gemfileLock.Sources)-1]
+			if field := gemfileLockFieldRegex.FindStringSubmatch(line); field != nil {
+				switch field[1] {
+				case "remote":
+					source.Remote = field[2]
+				case "revision":
+					source.Revision = field[2]
+				}
+				continue
+			}
+			if spec := gemfileLockSpecRegex.FindStringSubmatch(line); spec != nil {
+				version, platform, _ := strings.Cut(spec[2], "-")
+				source.Specs = append(source.Specs, GemfileLockSpec{Name: spec[1], Version: version, Platform: platform})
+			}
+		case "DEPENDENCIES":
+			name, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			gemfileLock.Dependencies = append(gemfileLock.Dependencies, strings.TrimSuffix(name, "!"))
+		case "BUNDLED WITH":
+			gemfileLock.BundledWith = strings.TrimSpace(line)
+		}
+	}
+	return gemfileLock
+}
+
+func buildAtlasGemfileLock(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	gemfileLock := parseGemfileLock(string(fileBytes))
+
+	atlas := Atlas{
+		name:         "",
+		language:     RUBY,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	lockedGems, lockedNames := map[string]bool{}, map[string]bool{}
+	for _, source := range gemfileLock.Sources {
+		for _, spec := range source.Specs {
+			key := strings.Join([]string{spec.Name, spec.Version, source.Remote}, " ")
+			if lockedGems[key] || matchRegExpPatterns(ignoredPatterns, spec.Name) {
+				continue
+			}
+			lockedGems[key], lockedNames[spec.Name] = true, true
+
+			var dep *Dependency
+			switch source.Kind {
+			case "GEM":
+				dep = NewRubyDependency(spec.Name, spec.Version).(*Dependency)
+				dep.PackageIndex = rubyGemsIndex(source.Remote)
+			case "GIT":
+				dep = &Dependency{Name: spec.Name, VersionCurrentLiteral: spec.Version, LocalPath: source.Remote}
+				if source.Revision != "" {
+					dep.LocalPath += "#" + source.Revision
+				}
+			default:
+				dep = &Dependency{Name: spec.Name, VersionCurrentLiteral: spec.Version, LocalPath: source.Remote}
+			}
+			dep.Indirect = !containsString(gemfileLock.Dependencies, spec.Name)
+			atlas.appendDependency(dep)
+		}
+	}
+	if gemfileLock.BundledWith != "" && !lockedNames["bundler"] && !matchRegExpPatterns(ignoredPatterns, "bundler") {
+		atlas.appendDependency(NewRubyDependency("bundler", gemfileLock.BundledWith))
+	}
+	return &atlas
+}
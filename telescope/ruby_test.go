@@ -0,0 +1,200 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGemVersionCompare(t *testing.T) {
+
+	params := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0", b: "1.0.0", expected: 0},
+		{a: "7.1.0.alpha", b: "7.1.0", expected: -1},
+		{a: "1.0.0.rc1", b: "1.0.0.beta2", expected: 1},
+		{a: "1.0.0.rc1", b: "1.0.0.rc2", expected: -1},
+		{a: "1.13.10", b: "1.13.9", expected: 1},
+		{a: "2.0.0.1", b: "2.0.0", expected: 1},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.a+" "+param.b,
+			func(t *testing.T) {
+				t.Parallel()
+				a, _ := NewGemVersion(param.a)
+				b, _ := NewGemVersion(param.b)
+				assert.Equal(t, a.Compare(b), param.expected)
+			},
+		)
+	}
+}
+
+func TestGemVersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{current: "6.1.7", latest: "7.0.4", expected: MAJOR},
+		{current: "7.0.4", latest: "7.1.0", expected: MINOR},
+		{current: "7.0.4", latest: "7.0.4.1", expected: PATCH},
+		{current: "7.1.0.alpha", latest: "7.1.0", expected: PATCH},
+		{current: "7.0.4", latest: "7.0.4", expected: UP_TO_DATE},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.current+" "+param.latest,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewGemVersion(param.current)
+				latest, _ := NewGemVersion(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestParseGemfileLock(t *testing.T) {
+
+	gemfileLock := parseGemfileLock(`GIT
+  remote: https://github.com/rails/rails.git
+  revision: 0c8d2a5
+  branch: main
+  specs:
+    rails (7.1.0.alpha)
+      actioncable (= 7.1.0.alpha)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.13.10-arm64-darwin)
+      racc (~> 1.4)
+    nokogiri (1.13.10-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.6.2)
+
+PLATFORMS
+  arm64-darwin
+  x86_64-linux
+
+DEPENDENCIES
+  nokogiri (~> 1.13)
+  rails!
+
+BUNDLED WITH
+   2.3.26
+`)
+
+	assert.Equal(t, len(gemfileLock.Sources), 2)
+	assert.Equal(t, gemfileLock.Sources[0].Kind, "GIT")
+	assert.Equal(t, gemfileLock.Sources[0].Revision, "0c8d2a5")
+	assert.Equal(t, gemfileLock.Sources[0].Specs, []GemfileLockSpec{{Name: "rails", Version: "7.1.0.alpha"}})
+	assert.Equal(t, gemfileLock.Sources[1].Remote, "https://rubygems.org/")
+	assert.Equal(t, gemfileLock.Sources[1].Specs[1], GemfileLockSpec{Name: "nokogiri", Version: "1.13.10", Platform: "x86_64-linux"})
+	assert.Equal(t, gemfileLock.Dependencies, []string{"nokogiri", "rails"})
+	assert.Equal(t, gemfileLock.BundledWith, "2.3.26")
+}
+
+func TestQueryVersionsRuby(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/versions/nokogiri.json":
+				w.Write([]byte(`[
+	{"number": "1.14.0.rc1", "platform": "ruby", "created_at": "2022-12-20T00:00:00.000Z"},
+	{"number": "1.13.10", "platform": "x86_64-linux", "created_at": "2022-12-07T00:00:00.000Z"},
+	{"number": "1.13.10", "platform": "ruby", "created_at": "2022-12-06T00:00:00.000Z"},
+	{"number": "1.13.9", "platform": "ruby", "created_at": "2022-10-18T00:00:00.000Z"}
+]`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	host := RubyGemsHost
+	RubyGemsHost = server.URL
+	defer func() { RubyGemsHost = host }()
+
+	nokogiri := NewRubyDependency("nokogiri", "1.13.9").(*Dependency)
+	nokogiri.queryVersionsRuby(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, nokogiri.VersionLatest.String(), "1.13.10")
+	assert.Equal(t, nokogiri.VersionLatestPrerelease.String(), "1.14.0.rc1")
+	assert.False(t, nokogiri.Yanked)
+	assert.Equal(t, nokogiri.GetOutdatedScope(), PATCH)
+
+	yanked := NewRubyDependency("nokogiri", "1.13.8").(*Dependency)
+	yanked.queryVersionsRuby(ReleasePolicy{})
+	assert.True(t, yanked.Yanked)
+}
+
+func TestBuildAtlasGemfileLock(t *testing.T) {
+
+	atlas := buildAtlasGemfileLock(
+		[]byte(`GIT
+  remote: https://github.com/rails/rails.git
+  revision: 0c8d2a5
+  specs:
+    rails (7.1.0.alpha)
+
+PATH
+  remote: .
+  specs:
+    mygem (0.1.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.13.10-arm64-darwin)
+      racc (~> 1.4)
+    nokogiri (1.13.10-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.6.2)
+
+GEM
+  remote: https://gems.example.com/
+  specs:
+    internal (2.0.0)
+
+DEPENDENCIES
+  internal!
+  mygem!
+  nokogiri (~> 1.13)
+  rails!
+
+BUNDLED WITH
+   2.3.26
+`),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, RUBY)
+	assert.Equal(t, len(atlas.dependencies), 6)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).LocalPath, "https://github.com/rails/rails.git#0c8d2a5")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).GetOutdatedScope(), LOCAL)
+	nokogiri := atlas.dependencies[2].(*Dependency)
+	assert.Equal(t, nokogiri.VersionCurrent.String(), "1.13.10")
+	assert.Equal(t, nokogiri.PackageIndex, "")
+	assert.False(t, nokogiri.Indirect)
+	assert.True(t, atlas.dependencies[3].(*Dependency).Indirect)
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).PackageIndex, "https://gems.example.com")
+	bundler := atlas.dependencies[5].(*Dependency)
+	assert.Equal(t, bundler.Name, "bundler")
+	assert.Equal(t, bundler.VersionCurrent.String(), "2.3.26")
+}
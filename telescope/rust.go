@@ -0,0 +1,336 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultCratesIndex = "https://index.crates.io"
+	cratesIoGitIndex   = "https://github.com/rust-lang/crates.io-index"
+)
+
+var CratesIndex = defaultCratesIndex
+
+type CargoVersion struct {
+	*semver.Version
+}
+
+type CratesIndexEntry struct {
+	Name    string `json:"name"`
+	Vers    string `json:"vers"`
+	Yanked  bool   `json:"yanked"`
+	Pubtime string `json:"pubtime"`
+}
+
+type CargoLockPackage struct {
+	Name         string   `toml:"name"`
+	Version      string   `toml:"version"`
+	Source       string   `toml:"source"`
+	Dependencies []string `toml:"dependencies"`
+}
+
+type CargoLock struct {
+	Packages []CargoLockPackage `toml:"package"`
+}
+
+type CargoTomlDependencies struct {
+	Dependencies      map[string]interface{} `toml:"dependencies"`
+	DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+	BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+}
+
+type CargoToml struct {
+	Package struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	CargoTomlDependencies
+	Target    map[string]CargoTomlDependencies `toml:"target"`
+	Workspace struct {
+		Members      []string               `toml:"members"`
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	} `toml:"workspace"`
+}
+
+func NewCargoVersion(version string) (*CargoVersion, error) {
+
+	semanticVersion, err := NewSematicVersion(version, true)
+	if err != nil {
+		return nil, err
+	}
+	return &CargoVersion{semanticVersion}, nil
+}
+
+func (v *CargoVersion) Compare(other IVersion) int {
+
+	return v.Version.Compare(other.(*CargoVersion).Version)
+}
+
+// OutdatedScope treats a minor update of a 0.x crate as a major one, and so a
+// patch update of a 0.0.x crate, since Cargo considers them incompatible.
+func (v *CargoVersion) OutdatedScope(other IVersion) OutdatedScope {
+
+	latest := other.(*CargoVersion)
+	switch {
+	case v.Major() > 0 || latest.Major() > 0:
+		return getExtendedScope(v, latest)
+	case v.Compare(latest) >= 0:
+		return UP_TO_DATE
+	case latest.Minor() > v.Minor():
+		return MAJOR
+	case v.Minor() == 0 && latest.Patch() > v.Patch():
+		return MAJOR
+	default:
+		return PATCH
+	}
+}
+
+func (d *Dependency) queryVersionsRust(policy ReleasePolicy) {
+
+	index := d.PackageIndex
+	if index == "" {
+		index = CratesIndex
+	}
+	url := fmt.Sprintf("%s/%s", strings.TrimRight(index, "/"), cratesIndexPath(d.Name))
+	statusCode, body := queryRegistry(url)
+	if statusCode != http.StatusOK {
+		logrus.Debug(fmt.Sprintf("failed to query crate index %s", url))
+		return
+	}
+
+	versions := []IVersion{}
+	releaseTimes := map[string]time.Time{}
+	yankedReleases := map[string]string{}
+	for _, line := range strings.Split(string(body), "\n") {
+		var entry CratesIndexEntry
+		if strings.TrimSpace(line) == "" || json.Unmarshal([]byte(line), &entry) != nil {
+			continue
+		}
+		cargoVersion, err := NewCargoVersion(entry.Vers)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", entry.Vers))
+			continue
+		}
+		versions = append(versions, cargoVersion)
+		releaseTimes[entry.Vers], _ = time.Parse(time.RFC3339, entry.Pubtime)
+		if entry.Yanked {
+			yankedReleases[entry.Vers] = ""
+			d.Yanked = d.Yanked || (d.VersionCurrent != nil && compareVersions(cargoVersion, d.VersionCurrent) == 0)
+		}
+	}
+
+	d.selectLatestVersions(
+		versions,
+		policy,
+		filterYanked(yankedReleases),
+		filterReleaseAge(
+			policy.MinAge,
+			func(version IVersion) time.Time {
+				return releaseTimes[version.Original()]
+			},
+		),
+	)
+}
+
+func cratesIndexPath(name string) string {
+
+	name = strings.ToLower(name)
+	switch len(name) {
+	case 1, 2:
+		return fmt.Sprintf("%d/%s", len(name), name)
+	case 3:
+		return fmt.Sprintf("3/%s/%s", name[:1], name)
+	default:
+		return fmt.Sprintf("%s/%s/%s", name[:2], name[2:4], name)
+	}
+}
+
+func cargoRegistryIndex(source string) (string, bool) {
+
+	switch {
+	case source == "registry+"+cratesIoGitIndex || strings.HasPrefix(source, "sparse+"+defaultCratesIndex):
+		return "", true
+	case strings.HasPrefix(source, "sparse+"):
+		return strings.TrimRight(strings.TrimPrefix(source, "sparse+"), "/"), true
+	case strings.HasPrefix(source, "registry+"):
+		// git indexes of other registries can not be read over http, the
+		// package is reported unknown unless the url serves a sparse index
+		return strings.TrimPrefix(source, "registry+"), true
+	default:
+		return "", false
+	}
+}
+
+type cargoManifest struct {
+	directory string
+	manifest  *CargoToml
+}
+
+func readCargoManifests(directory string) []cargoManifest {
+
+	root := readCargoToml(filepath.Join(directory, "Cargo.toml"))
+	if root == nil {
+		return []cargoManifest{}
+	}
+
+	manifests := []cargoManifest{{directory: ".", manifest: root}}
+	for _, member := range root.Workspace.Members {
+		memberPaths, _ := filepath.Glob(filepath.Join(directory, member))
+		for _, memberPath := range memberPaths {
+			manifest := readCargoToml(filepath.Join(memberPath, "Cargo.toml"))
+			if manifest == nil {
+				continue
+			}
+			relativePath, _ := filepath.Rel(directory, memberPath)
+			manifests = append(manifests, cargoManifest{directory: relativePath, manifest: manifest})
+		}
+	}
+	return manifests
+}
+
+func readCargoToml(filePath string) *CargoToml {
+
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("no Cargo.toml found: %s", err.Error()))
+		return nil
+	}
+
+	var cargoToml CargoToml
+	err = toml.Unmarshal(fileBytes, &cargoToml)
+	if err != nil {
+		panic(err)
+	}
+	return &cargoToml
+}
+
+func (c *CargoToml) declaredSections() map[string][]map[string]interface{} {
+
+	sections := map[string][]map[string]interface{}{
+		mainGroup: {c.Dependencies},
+		devGroup:  {c.DevDependencies},
+		"build":   {c.BuildDependencies},
+	}
+	for _, target := range sortedKeys(c.Target) {
+		sections[mainGroup] = append(sections[mainGroup], c.Target[target].Dependencies)
+		sections[devGroup] = append(sections[devGroup], c.Target[target].DevDependencies)
+		sections["build"] = append(sections["build"], c.Target[target].BuildDependencies)
+	}
+	return sections
+}
+
+func resolveCargoDeclaration(name string, declaration interface{}, directory string, root *CargoToml) (string, string) {
+
+	table, ok := declaration.(map[string]interface{})
+	if !ok {
+		return name, ""
+	}
+	if inherited, _ := table["workspace"].(bool); inherited {
+		if workspaceDeclaration, ok := root.Workspace.Dependencies[name]; ok {
+			return resolveCargoDeclaration(name, workspaceDeclaration, ".", root)
+		}
+	}
+	crate, location := name, ""
+	if renamed, ok := table["package"].(string); ok {
+		crate = renamed
+	}
+	if declaredPath, ok := table["path"].(string); ok {
+		location = filepath.Join(directory, declaredPath)
+	}
+	return crate, location
+}
+
+func buildAtlasCargoLock(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	var cargoLock CargoLock
+	err := toml.Unmarshal(fileBytes, &cargoLock)
+	if err != nil {
+		panic(err)
+	}
+
+	atlas := Atlas{
+		name:         "",
+		language:     RUST,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	manifests := readCargoManifests(filepath.Dir(filePath))
+	members := map[string]bool{}
+	declaredGroups := map[string][]string{}
+	localPaths := map[string]string{}
+	for _, member := range manifests {
+		members[member.manifest.Package.Name] = true
+		for group, sections := range member.manifest.declaredSections() {
+			for _, section := range sections {
+				for _, name := range sortedKeys(section) {
+					crate, location := resolveCargoDeclaration(name, section[name], member.directory, manifests[0].manifest)
+					declaredGroups[group] = append(declaredGroups[group], crate)
+					if location != "" {
+						localPaths[crate] = location
+					}
+				}
+			}
+		}
+	}
+	if len(manifests) > 0 {
+		atlas.name = manifests[0].manifest.Package.Name
+	}
+	isMember := func(pkg CargoLockPackage) bool {
+		return pkg.Source == "" && (len(manifests) == 0 || members[pkg.Name])
+	}
+
+	directPackages := map[string]bool{}
+	requirements := map[string][]string{}
+	for _, pkg := range cargoLock.Packages {
+		for _, dependency := range pkg.Dependencies {
+			// dependencies are followed by their version, and their source,
+			// when several packages share the name
+			fields := append(strings.Fields(dependency), "", "")
+			requirements[pkg.Name] = append(requirements[pkg.Name], fields[0])
+			if isMember(pkg) {
+				directPackages[strings.TrimSpace(fields[0]+" "+fields[1])] = true
+			}
+		}
+	}
+	groups := propagateGroups(declaredGroups, requirements)
+
+	for _, pkg := range cargoLock.Packages {
+		if isMember(pkg) || matchRegExpPatterns(ignoredPatterns, pkg.Name) {
+			continue
+		}
+
+		var dep *Dependency
+		index, registry := cargoRegistryIndex(pkg.Source)
+		switch {
+		case registry:
+			dep = NewRustDependency(pkg.Name, pkg.Version).(*Dependency)
+			dep.PackageIndex = index
+		case pkg.Source != "":
+			dep = &Dependency{Name: pkg.Name, VersionCurrentLiteral: pkg.Version, LocalPath: pkg.Source}
+		case localPaths[pkg.Name] != "":
+			dep = &Dependency{Name: pkg.Name, VersionCurrentLiteral: pkg.Version, LocalPath: localPaths[pkg.Name]}
+		default:
+			continue
+		}
+		dep.Indirect = !directPackages[pkg.Name] && !directPackages[pkg.Name+" "+pkg.Version]
+		dep.Groups = groups[pkg.Name]
+		atlas.appendDependency(dep)
+	}
+	return &atlas
+}
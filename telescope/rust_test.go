@@ -0,0 +1,233 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCargoVersionOutdatedScope(t *testing.T) {
+
+	params := []struct {
+		current  string
+		latest   string
+		expected OutdatedScope
+	}{
+		{current: "1.2.3", latest: "2.0.0", expected: MAJOR},
+		{current: "1.2.3", latest: "1.3.0", expected: MINOR},
+		{current: "1.2.3", latest: "1.2.4", expected: PATCH},
+		{current: "0.3.1", latest: "1.0.0", expected: MAJOR},
+		{current: "0.3.1", latest: "0.4.0", expected: MAJOR},
+		{current: "0.3.1", latest: "0.3.5", expected: PATCH},
+		{current: "0.0.3", latest: "0.0.4", expected: MAJOR},
+		{current: "0.4.0", latest: "0.3.9", expected: UP_TO_DATE},
+		{current: "0.3.1", latest: "0.3.1", expected: UP_TO_DATE},
+		{current: "0.1.0-alpha.1", latest: "0.1.0", expected: PATCH},
+		{current: "0.0.3-alpha.1", latest: "0.0.3", expected: PATCH},
+		{current: "1.0.0-rc.1", latest: "1.0.0", expected: PATCH},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.current+" "+param.latest,
+			func(t *testing.T) {
+				t.Parallel()
+				current, _ := NewCargoVersion(param.current)
+				latest, _ := NewCargoVersion(param.latest)
+				assert.Equal(t, current.OutdatedScope(latest), param.expected)
+			},
+		)
+	}
+}
+
+func TestCratesIndexPath(t *testing.T) {
+
+	params := []struct {
+		name     string
+		expected string
+	}{
+		{name: "a", expected: "1/a"},
+		{name: "cc", expected: "2/cc"},
+		{name: "syn", expected: "3/s/syn"},
+		{name: "Serde_json", expected: "se/rd/serde_json"},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.name,
+			func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, cratesIndexPath(param.name), param.expected)
+			},
+		)
+	}
+}
+
+func TestQueryVersionsRust(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/ra/nd/rand":
+				w.Write([]byte(`{"name":"rand","vers":"0.7.3","deps":[],"cksum":"a","features":{},"yanked":false}
+{"name":"rand","vers":"0.8.4","deps":[],"cksum":"b","features":{},"yanked":true}
+{"name":"rand","vers":"0.8.5","deps":[],"cksum":"c","features":{},"yanked":false,"pubtime":"2022-02-14T00:00:00Z"}
+{"name":"rand","vers":"0.9.0","deps":[],"cksum":"d","features":{},"yanked":true}
+{"name":"rand","vers":"0.9.0-alpha.1","deps":[],"cksum":"e","features":{},"yanked":false}
+`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	index := CratesIndex
+	CratesIndex = server.URL
+	defer func() { CratesIndex = index }()
+
+	rand := NewRustDependency("rand", "0.8.4").(*Dependency)
+	rand.queryVersionsRust(ReleasePolicy{})
+	assert.Equal(t, rand.VersionLatest.String(), "0.8.5")
+	assert.Equal(t, rand.VersionLatestPrerelease.String(), "0.9.0-alpha.1")
+	assert.True(t, rand.Yanked)
+	assert.Equal(t, rand.GetOutdatedScope(), PATCH)
+
+	legacy := NewRustDependency("rand", "0.7.3").(*Dependency)
+	legacy.queryVersionsRust(ReleasePolicy{})
+	assert.False(t, legacy.Yanked)
+	assert.Equal(t, legacy.GetOutdatedScope(), MAJOR)
+
+	registryIndex, registry := cargoRegistryIndex("sparse+https://cargo.example.com/index/")
+	assert.True(t, registry)
+	assert.Equal(t, registryIndex, "https://cargo.example.com/index")
+	_, registry = cargoRegistryIndex("git+https://github.com/foo/bar?branch=main#abc123")
+	assert.False(t, registry)
+}
+
+func TestBuildAtlasCargoLock(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		"Cargo.toml": `[package]
+name = "app"
+version = "0.1.0"
+
+[workspace]
+members = ["crates/*"]
+
+[workspace.dependencies]
+serde = { version = "1.0", features = ["derive"] }
+
+[dependencies]
+rand = "0.8"
+serde = { workspace = true }
+helper = { path = "../helper" }
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+`,
+		"crates/cli/Cargo.toml": `[package]
+name = "app-cli"
+version = "0.1.0"
+
+[dependencies]
+app = { path = "../.." }
+
+[dev-dependencies]
+assert_cmd = "2.0"
+
+[build-dependencies]
+cc = "1.0"
+`,
+		"Cargo.lock": `version = 4
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "helper",
+ "libc",
+ "rand 0.8.5",
+ "serde",
+]
+
+[[package]]
+name = "app-cli"
+version = "0.1.0"
+dependencies = [
+ "app",
+ "assert_cmd",
+ "cc",
+]
+
+[[package]]
+name = "assert_cmd"
+version = "2.0.12"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "cc"
+version = "1.0.83"
+source = "sparse+https://cargo.example.com/index/"
+
+[[package]]
+name = "helper"
+version = "0.2.0"
+
+[[package]]
+name = "libc"
+version = "0.2.150"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.7.3"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "rand"
+version = "0.8.5"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "libc",
+ "rand 0.7.3",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.193"
+source = "git+https://github.com/serde-rs/serde?branch=master#abc123"
+`,
+	})
+
+	lockPath := filepath.Join(project, "Cargo.lock")
+	atlas := buildAtlasCargoLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.name, "app")
+	assert.Equal(t, atlas.language, RUST)
+	assert.Equal(t, len(atlas.dependencies), 7)
+	assertCmd := atlas.dependencies[0].(*Dependency)
+	assert.False(t, assertCmd.Indirect)
+	assert.True(t, assertCmd.IsDevelopment())
+	cc := atlas.dependencies[1].(*Dependency)
+	assert.Equal(t, cc.PackageIndex, "https://cargo.example.com/index")
+	assert.Equal(t, cc.Groups, []string{"build"})
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).LocalPath, "../helper")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).Groups, []string{"main"})
+	assert.True(t, atlas.dependencies[4].(*Dependency).Indirect)
+	rand := atlas.dependencies[5].(*Dependency)
+	assert.False(t, rand.Indirect)
+	assert.Equal(t, rand.VersionCurrent.String(), "0.8.5")
+	assert.Equal(t, atlas.dependencies[6].(*Dependency).GetOutdatedScope(), LOCAL)
+}
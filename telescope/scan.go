@@ -0,0 +1,303 @@
+package telescope
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ScannedFile struct {
+	Project  string
+	FilePath string
+}
+
+type gitignoreRule struct {
+	base    string
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+func DiscoverDependencyFiles(root string, excludes []string) ([]ScannedFile, error) {
+
+	rules := []gitignoreRule{}
+	for _, exclude := range excludes {
+		if rule, ok := newGitignoreRule("", exclude); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	candidates := map[string][]string{}
+	err := filepath.WalkDir(
+		root,
+		func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			relPath = filepath.ToSlash(relPath)
+			if relPath != "." && (entry.Name() == ".git" || isGitignored(rules, relPath, entry.IsDir())) {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if entry.IsDir() {
+				base := relPath
+				if base == "." {
+					base = ""
+				}
+				rules = append(rules, readGitignore(filepath.Join(path, ".gitignore"), base)...)
+				return nil
+			}
+			if _, _, ok := classifyDependencyFile(relPath); ok && entry.Type().IsRegular() {
+				directory := filepath.Dir(path)
+				candidates[directory] = append(candidates[directory], path)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scannedFiles := []ScannedFile{}
+	for _, directory := range sortedKeys(candidates) {
+		locked := map[Language]bool{}
+		for _, path := range candidates[directory] {
+			if language, lock, _ := classifyDependencyFile(filepath.ToSlash(path)); lock {
+				locked[language] = true
+			}
+		}
+		for _, path := range candidates[directory] {
+			language, lock, _ := classifyDependencyFile(filepath.ToSlash(path))
+			if locked[language] && !lock {
+				continue
+			}
+			scannedFiles = append(scannedFiles, ScannedFile{Project: scannedProject(root, path), FilePath: path})
+		}
+	}
+	sort.SliceStable(
+		scannedFiles,
+		func(i, j int) bool {
+			return scannedFiles[i].Project < scannedFiles[j].Project
+		},
+	)
+	return scannedFiles, nil
+}
+
+func Scan(
+	root string,
+	excludes []string,
+	options AtlasOptions,
+	desiredScope OutdatedScope,
+	skipUnknown bool,
+) (bool, error) {
+
+	scannedFiles, err := DiscoverDependencyFiles(root, excludes)
+	if err != nil {
+		return false, err
+	}
+
+	criticalFound := false
+	project := ""
+	for _, scannedFile := range scannedFiles {
+		atlas := newScannedAtlas(scannedFile.FilePath, options)
+		if atlas == nil || len(atlas.(*Atlas).dependencies) == 0 {
+			continue
+		}
+		if scannedFile.Project != project {
+			project = scannedFile.Project
+			fmt.Printf("\033[1m\n%s %s %s\n\033[0m", strings.Repeat("#", 8), project, strings.Repeat("#", 8))
+		}
+		fmt.Printf("\033[1m\n--- %s (%s)\n\033[0m", filepath.Base(scannedFile.FilePath), atlas.(*Atlas).language)
+		criticalFound = atlas.ReportOutdated(desiredScope, skipUnknown) || criticalFound
+	}
+	return criticalFound, nil
+}
+
+func newScannedAtlas(filePath string, options AtlasOptions) (atlas IReportable) {
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.Warn(fmt.Sprintf("skip %s: %v", filePath, recovered))
+			atlas = nil
+		}
+	}()
+	return NewAtlas(filepath.ToSlash(filePath), options)
+}
+
+func scannedProject(root, path string) string {
+
+	project, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return filepath.Dir(path)
+	}
+	project = filepath.ToSlash(project)
+	if idx := strings.Index("/"+project+"/", "/.github/"); idx >= 0 {
+		project = strings.TrimSuffix(project[:idx], "/")
+		if project == "" {
+			project = "."
+		}
+	}
+	return project
+}
+
+func classifyDependencyFile(filePath string) (Language, bool, bool) {
+
+	fileName := filePath[strings.LastIndex(filePath, "/")+1:]
+	switch {
+	case fileName == "go.work":
+		return GO, true, true
+	case fileName == "go.mod":
+		return GO, false, true
+	case fileName == "poetry.lock" || fileName == "Pipfile.lock" || fileName == "uv.lock" || fileName == "pdm.lock":
+		return PYTHON, true, true
+	case fileName == "environment.yml" || fileName == "environment.yaml":
+		return CONDA, false, true
+	case fileName == "conda-lock.yml" || fileName == "conda-lock.yaml":
+		return CONDA, true, true
+	case fileName == "package-lock.json" || fileName == "npm-shrinkwrap.json" ||
+		fileName == "yarn.lock" || fileName == "pnpm-lock.yaml":
+		return JAVASCRIPT, true, true
+	case fileName == "Cargo.lock":
+		return RUST, true, true
+	case fileName == "Gemfile.lock" || fileName == "gems.locked":
+		return RUBY, true, true
+	case fileName == "pom.xml" || strings.HasSuffix(fileName, ".versions.toml"):
+		return JAVA, false, true
+	case fileName == "composer.lock":
+		return PHP, true, true
+	case fileName == "packages.lock.json":
+		return DOTNET, true, true
+	case fileName == "Directory.Packages.props":
+		return DOTNET, false, true
+	case isWorkflowFile(filePath):
+		return ACTIONS, false, true
+	case isDockerfile(fileName):
+		return DOCKER, false, true
+	case fileName == ".terraform.lock.hcl":
+		return TERRAFORM, true, true
+	case strings.HasSuffix(fileName, ".tf"):
+		return TERRAFORM, false, true
+	case fileName == "Chart.lock":
+		return HELM, true, true
+	case fileName == "Chart.yaml":
+		return HELM, false, true
+	case fileName == "pyproject.toml" || requirementsFilePattern.MatchString(fileName):
+		return PYTHON, false, true
+	}
+	return 0, false, false
+}
+
+func readGitignore(filePath, base string) []gitignoreRule {
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	rules := []gitignoreRule{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := newGitignoreRule(base, scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func newGitignoreRule(base, line string) (gitignoreRule, bool) {
+
+	pattern := strings.TrimRight(line, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return gitignoreRule{}, false
+	}
+	rule := gitignoreRule{base: base}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate, pattern = true, pattern[1:]
+	} else if strings.HasPrefix(pattern, `\`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly, pattern = true, strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return gitignoreRule{}, false
+	}
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	var expression strings.Builder
+	if !anchored {
+		expression.WriteString("(?:.*/)?")
+	}
+	for idx := 0; idx < len(pattern); idx++ {
+		switch {
+		case strings.HasPrefix(pattern[idx:], "**/"):
+			expression.WriteString("(?:.*/)?")
+			idx += 2
+		case strings.HasPrefix(pattern[idx:], "/**") && idx+3 == len(pattern):
+			expression.WriteString("/.*")
+			idx += 2
+		case pattern[idx] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[idx] == '?':
+			expression.WriteString("[^/]")
+		case pattern[idx] == '[' && strings.Contains(pattern[idx:], "]"):
+			end := idx + strings.Index(pattern[idx:], "]")
+			class := pattern[idx+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expression.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			idx = end
+		case pattern[idx] == '\\' && idx+1 < len(pattern):
+			idx++
+			expression.WriteString(regexp.QuoteMeta(pattern[idx : idx+1]))
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[idx : idx+1]))
+		}
+	}
+
+	compiled, err := regexp.Compile("^" + expression.String() + "$")
+	if err != nil {
+		logrus.Debug(fmt.Sprintf("invalid ignore pattern %s: %s", line, err.Error()))
+		return gitignoreRule{}, false
+	}
+	rule.pattern = compiled
+	return rule, true
+}
+
+func isGitignored(rules []gitignoreRule, relPath string, isDir bool) bool {
+
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		path := relPath
+		if rule.base != "" {
+			if !strings.HasPrefix(relPath, rule.base+"/") {
+				continue
+			}
+			path = strings.TrimPrefix(relPath, rule.base+"/")
+		}
+		if rule.pattern.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
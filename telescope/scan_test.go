@@ -0,0 +1,94 @@
+package telescope
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGitignored(t *testing.T) {
+
+	rules := []gitignoreRule{}
+	for _, line := range []string{"# build outputs", "node_modules/", "*.log", "/dist", "!keep.log", "docs/**/generated"} {
+		if rule, ok := newGitignoreRule("", line); ok {
+			rules = append(rules, rule)
+		}
+	}
+	nested, _ := newGitignoreRule("services/api", "fixtures/")
+	rules = append(rules, nested)
+
+	params := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{path: "node_modules", isDir: true, expected: true},
+		{path: "web/node_modules", isDir: true, expected: true},
+		{path: "node_modules", isDir: false, expected: false},
+		{path: "logs/debug.log", isDir: false, expected: true},
+		{path: "logs/keep.log", isDir: false, expected: false},
+		{path: "dist", isDir: true, expected: true},
+		{path: "web/dist", isDir: true, expected: false},
+		{path: "docs/api/v1/generated", isDir: true, expected: true},
+		{path: "services/api/fixtures", isDir: true, expected: true},
+		{path: "services/web/fixtures", isDir: true, expected: false},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.path,
+			func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, isGitignored(rules, param.path, param.isDir), param.expected)
+			},
+		)
+	}
+}
+
+func TestDiscoverDependencyFiles(t *testing.T) {
+
+	root := writeFiles(t, map[string]string{
+		".gitignore":                          "node_modules/\n.terraform/\n",
+		"go.mod":                              "module example.com/app\n",
+		".github/workflows/build.yml":         "on: push\n",
+		"services/api/pyproject.toml":         "[project]\nname = \"api\"\n",
+		"services/api/poetry.lock":            "",
+		"services/api/Dockerfile":             "FROM python:3.11\n",
+		"services/web/package-lock.json":      "{}",
+		"services/web/node_modules/a/go.mod":  "module a\n",
+		"infra/main.tf":                       "",
+		"infra/.terraform.lock.hcl":           "",
+		"infra/.terraform/modules/vpc/go.mod": "module vpc\n",
+		"testdata/requirements.txt":           "",
+		"README.md":                           "",
+	})
+
+	scannedFiles, err := DiscoverDependencyFiles(root, []string{"testdata/"})
+	assert.Nil(t, err)
+
+	discovered := []ScannedFile{}
+	for _, scannedFile := range scannedFiles {
+		relPath, _ := filepath.Rel(root, scannedFile.FilePath)
+		discovered = append(discovered, ScannedFile{Project: scannedFile.Project, FilePath: filepath.ToSlash(relPath)})
+	}
+	assert.Equal(
+		t,
+		discovered,
+		[]ScannedFile{
+			{Project: ".", FilePath: "go.mod"},
+			{Project: ".", FilePath: ".github/workflows/build.yml"},
+			{Project: "infra", FilePath: "infra/.terraform.lock.hcl"},
+			{Project: "services/api", FilePath: "services/api/Dockerfile"},
+			{Project: "services/api", FilePath: "services/api/poetry.lock"},
+			{Project: "services/web", FilePath: "services/web/package-lock.json"},
+		},
+	)
+}
+
+func TestNewScannedAtlasInvalid(t *testing.T) {
+
+	root := writeFiles(t, map[string]string{"go.work": "go 1.21\n\nuse ./missing\n"})
+	assert.Nil(t, newScannedAtlas(filepath.Join(root, "go.work"), AtlasOptions{}))
+}
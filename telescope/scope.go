@@ -13,18 +13,30 @@ const (
 	MAJOR
 	MINOR
 	PATCH
+	UNTAGGED
+	LOCAL
+	UNPINNED
+	CONSTRAINT_OUTDATED
 	UNKNOWN
 )
 
-var OutdatedScopeSeries [5]OutdatedScope = [...]OutdatedScope{UP_TO_DATE, MAJOR, MINOR, PATCH, UNKNOWN}
-var OutdatedScopeLiteral [5]string = [...]string{"UP_TO_DATE", "MAJOR", "MINOR", "PATCH", "UNKNOWN"}
+var OutdatedScopeSeries [9]OutdatedScope = [...]OutdatedScope{
+	UP_TO_DATE, MAJOR, MINOR, PATCH, UNTAGGED, LOCAL, UNPINNED, CONSTRAINT_OUTDATED, UNKNOWN,
+}
+var OutdatedScopeLiteral [9]string = [...]string{
+	"UP_TO_DATE", "MAJOR", "MINOR", "PATCH", "UNTAGGED", "LOCAL", "UNPINNED", "CONSTRAINT_OUTDATED", "UNKNOWN",
+}
 
 var MapScopeColor map[OutdatedScope]int = map[OutdatedScope]int{
-	UP_TO_DATE: 92,
-	MAJOR:      91,
-	MINOR:      93,
-	PATCH:      94,
-	UNKNOWN:    97,
+	UP_TO_DATE:          92,
+	MAJOR:               91,
+	MINOR:               93,
+	PATCH:               94,
+	UNTAGGED:            95,
+	LOCAL:               96,
+	UNPINNED:            90,
+	CONSTRAINT_OUTDATED: 35,
+	UNKNOWN:             97,
 }
 
 func (o OutdatedScope) String() string {
@@ -17,9 +17,14 @@ func TestOutdatedScopeString(t *testing.T) {
 		{name: "major", scope: MAJOR, expected: "MAJOR"},
 		{name: "minor", scope: MINOR, expected: "MINOR"},
 		{name: "patch", scope: PATCH, expected: "PATCH"},
+		{name: "untagged", scope: UNTAGGED, expected: "UNTAGGED"},
+		{name: "local", scope: LOCAL, expected: "LOCAL"},
+		{name: "unpinned", scope: UNPINNED, expected: "UNPINNED"},
+		{name: "constraint outdated", scope: CONSTRAINT_OUTDATED, expected: "CONSTRAINT_OUTDATED"},
 		{name: "unknown", scope: UNKNOWN, expected: "UNKNOWN"},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -42,9 +47,14 @@ func TestOutdatedScopeStrToEnum(t *testing.T) {
 		{name: "major", scopeStr: "major", expected: MAJOR},
 		{name: "minor", scopeStr: "minor", expected: MINOR},
 		{name: "patch", scopeStr: "patch", expected: PATCH},
+		{name: "untagged", scopeStr: "untagged", expected: UNTAGGED},
+		{name: "local", scopeStr: "local", expected: LOCAL},
+		{name: "unpinned", scopeStr: "unpinned", expected: UNPINNED},
+		{name: "constraint outdated", scopeStr: "constraint_outdated", expected: CONSTRAINT_OUTDATED},
 		{name: "unknown", scopeStr: "unknown", expected: UNKNOWN},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -70,6 +80,7 @@ func TestGetTopScope(t *testing.T) {
 		{name: "different scopes 3", scopes: []OutdatedScope{PATCH, MINOR, PATCH}, expected: MINOR},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -90,6 +101,7 @@ func TestGetTopScopePanic(t *testing.T) {
 		{name: "no scope", scopes: []OutdatedScope{}},
 	}
 	for _, param := range params {
+		param := param
 
 		t.Run(
 			param.name,
@@ -0,0 +1,353 @@
+package telescope
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultTerraformRegistry = "https://registry.terraform.io"
+	terraformPublicHost      = "registry.terraform.io"
+	terraformDiscoveryUrl    = "%s/.well-known/terraform.json"
+	terraformVersionsUrl     = "%s%s/versions"
+)
+
+var TerraformRegistry = defaultTerraformRegistry
+
+var (
+	terraformClausePattern = regexp.MustCompile(`^\s*(=|!=|>=|<=|>|<|~>)?\s*v?([0-9]+(?:\.[0-9]+){0,2}(?:-[0-9A-Za-z.-]+)?)\s*$`)
+	terraformBlockPattern  = regexp.MustCompile(`^\s*([a-z_]+)\s+"([^"]*)"\s*\{`)
+	terraformAttrPattern   = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_-]*)\s*=\s*"([^"]*)"`)
+	terraformStringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	terraformSourcePattern = regexp.MustCompile(
+		`^(?:([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)+(?::[0-9]+)?)/)?([0-9A-Za-z][0-9A-Za-z_-]*)/([0-9A-Za-z][0-9A-Za-z_-]*)/([0-9a-z]+)(?://.*)?$`,
+	)
+)
+
+type TerraformBlock struct {
+	Label      string
+	Attributes map[string]string
+}
+
+type TerraformVersions struct {
+	Versions []struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+	Modules []struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"modules"`
+}
+
+type terraformClause struct {
+	operator  string
+	version   *semver.Version
+	precision int
+}
+
+type TerraformConstraint struct {
+	original string
+	clauses  []terraformClause
+}
+
+func NewTerraformConstraint(constraint string) (*TerraformConstraint, error) {
+
+	terraformConstraint := &TerraformConstraint{original: strings.TrimSpace(constraint)}
+	for _, clause := range strings.Split(constraint, ",") {
+		match := terraformClausePattern.FindStringSubmatch(clause)
+		if match == nil {
+			return nil, fmt.Errorf("invalid terraform constraint %s", constraint)
+		}
+		version, err := semver.NewVersion(match[2])
+		if err != nil {
+			return nil, err
+		}
+		numbers, _, _ := strings.Cut(match[2], "-")
+		operator := match[1]
+		if operator == "" {
+			operator = "="
+		}
+		terraformConstraint.clauses = append(
+			terraformConstraint.clauses,
+			terraformClause{operator: operator, version: version, precision: strings.Count(numbers, ".") + 1},
+		)
+	}
+	return terraformConstraint, nil
+}
+
+func (c *TerraformConstraint) String() string {
+	return c.original
+}
+
+func (c *TerraformConstraint) exactVersion() string {
+
+	if len(c.clauses) != 1 || c.clauses[0].operator != "=" || c.clauses[0].precision != 3 {
+		return ""
+	}
+	return c.clauses[0].version.Original()
+}
+
+func (c *TerraformConstraint) Admits(version IVersion) bool {
+
+	semanticVersion, ok := version.(*semver.Version)
+	if !ok {
+		return false
+	}
+	exact := false
+	for _, clause := range c.clauses {
+		if !clause.admits(semanticVersion) {
+			return false
+		}
+		exact = exact || clause.operator == "="
+	}
+	return semanticVersion.Prerelease() == "" || exact
+}
+
+func (c terraformClause) admits(version *semver.Version) bool {
+
+	result := version.Compare(c.version)
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+
+	// ~> only lets the rightmost number of the clause increase
+	if result < 0 || c.precision > 1 && version.Major() != c.version.Major() {
+		return false
+	}
+	return c.precision < 3 || version.Minor() == c.version.Minor()
+}
+
+func (d *Dependency) queryVersionsTerraform(policy ReleasePolicy) {
+
+	if policy.MinAge > 0 {
+		logrus.Warn(fmt.Sprintf("skip --min-age for %s: unknown release time", d.Name))
+	}
+
+	host, address, provider := terraformAddress(d.Name)
+	registry := TerraformRegistry
+	if registryUrl, err := url.Parse(TerraformRegistry); host != terraformPublicHost && (err != nil || registryUrl.Host != host) {
+		registry = "https://" + host
+	}
+	registry = strings.TrimRight(registry, "/")
+	service := "modules.v1"
+	if provider {
+		service = "providers.v1"
+	}
+
+	versionsUrl := fmt.Sprintf(terraformVersionsUrl, terraformServiceUrl(registry, service), address)
+	statusCode, body := queryRegistryWithHeader(versionsUrl, terraformHeader(host))
+	var terraformVersions TerraformVersions
+	if statusCode != http.StatusOK || json.Unmarshal(body, &terraformVersions) != nil {
+		logrus.Debug(fmt.Sprintf("failed to query terraform versions %s", versionsUrl))
+		return
+	}
+
+	literals := []string{}
+	for _, ver := range terraformVersions.Versions {
+		literals = append(literals, ver.Version)
+	}
+	for _, module := range terraformVersions.Modules {
+		for _, ver := range module.Versions {
+			literals = append(literals, ver.Version)
+		}
+	}
+	versions := []IVersion{}
+	for _, literal := range literals {
+		version, err := NewSematicVersion(literal, true)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("invalid version %s", literal))
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	d.selectLatestVersions(versions, policy)
+	if d.Constraint != nil {
+		d.VersionConstraintLatest = findLatestVersion(versions, d.Constraint.Admits)
+	}
+}
+
+func terraformServiceUrl(registry, service string) string {
+
+	discoveryUrl := fmt.Sprintf(terraformDiscoveryUrl, registry)
+	statusCode, body := queryRegistry(discoveryUrl)
+	services := map[string]interface{}{}
+	if statusCode != http.StatusOK || json.Unmarshal(body, &services) != nil {
+		logrus.Debug(fmt.Sprintf("failed to discover the services of %s", registry))
+	}
+	path, ok := services[service].(string)
+	if !ok {
+		path = "/v1/" + strings.TrimSuffix(service, ".v1") + "/"
+	}
+
+	base, err := url.Parse(discoveryUrl)
+	if err != nil {
+		return registry + path
+	}
+	reference, err := url.Parse(path)
+	if err != nil {
+		return registry + path
+	}
+	return strings.TrimRight(base.ResolveReference(reference).String(), "/") + "/"
+}
+
+func terraformHeader(host string) http.Header {
+
+	variable := "TF_TOKEN_" + strings.NewReplacer("-", "__", ".", "_").Replace(host)
+	token := os.Getenv(variable)
+	if token == "" {
+		return nil
+	}
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+func terraformAddress(name string) (string, string, bool) {
+
+	segments := strings.Split(name, "/")
+	switch {
+	case len(segments) == 3 && strings.Contains(segments[0], "."):
+		return strings.ToLower(segments[0]), segments[1] + "/" + segments[2], true
+	case len(segments) == 4:
+		return strings.ToLower(segments[0]), strings.Join(segments[1:], "/"), false
+	}
+	return terraformPublicHost, name, false
+}
+
+func terraformModuleAddress(source string) (string, bool) {
+
+	match := terraformSourcePattern.FindStringSubmatch(source)
+	if match == nil || match[1] == "github.com" || match[1] == "bitbucket.org" {
+		return "", false
+	}
+	address := strings.Join(match[2:5], "/")
+	if match[1] != "" {
+		address = match[1] + "/" + address
+	}
+	return address, true
+}
+
+func parseTerraformBlocks(content, blockType string) []TerraformBlock {
+
+	blocks := []TerraformBlock{}
+	depth := 0
+	var block *TerraformBlock
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if depth == 0 {
+			if match := terraformBlockPattern.FindStringSubmatch(line); match != nil && match[1] == blockType {
+				blocks = append(blocks, TerraformBlock{Label: match[2], Attributes: map[string]string{}})
+				block = &blocks[len(blocks)-1]
+			}
+		} else if depth == 1 && block != nil {
+			if match := terraformAttrPattern.FindStringSubmatch(line); match != nil {
+				block.Attributes[match[1]] = match[2]
+			}
+		}
+
+		code := terraformStringPattern.ReplaceAllString(line, `""`)
+		if idx := strings.Index(code, "#"); idx >= 0 {
+			code = code[:idx]
+		}
+		if idx := strings.Index(code, "//"); idx >= 0 {
+			code = code[:idx]
+		}
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if depth <= 0 {
+			depth, block = 0, nil
+		}
+	}
+	return blocks
+}
+
+func buildAtlasTerraformLock(
+	filePath string,
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	atlas := Atlas{
+		name:         "",
+		language:     TERRAFORM,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	for _, provider := range parseTerraformBlocks(string(fileBytes), "provider") {
+		if matchRegExpPatterns(ignoredPatterns, provider.Label) {
+			continue
+		}
+		atlas.appendDependency(NewTerraformDependency(provider.Label, provider.Attributes["version"]))
+	}
+
+	configurationPaths, _ := filepath.Glob(filepath.Join(filepath.Dir(filePath), "*.tf"))
+	reported := map[string]bool{}
+	for _, configurationPath := range configurationPaths {
+		configurationBytes, err := os.ReadFile(configurationPath)
+		if err != nil {
+			logrus.Debug(fmt.Sprintf("failed to read %s: %s", configurationPath, err.Error()))
+			continue
+		}
+		atlas.appendTerraformModules(configurationBytes, ignoredPatterns, reported)
+	}
+	return &atlas
+}
+
+func buildAtlasTerraformConfiguration(
+	fileBytes []byte,
+	ignoredPatterns []*regexp.Regexp,
+	criticalPatterns map[OutdatedScope][]criticalRule,
+) IReportable {
+
+	atlas := Atlas{
+		name:         "",
+		language:     TERRAFORM,
+		dependencies: []IDependable{},
+		criticalMap:  criticalPatterns,
+		outdatedMap:  map[OutdatedScope][]IDependable{},
+	}
+	atlas.appendTerraformModules(fileBytes, ignoredPatterns, map[string]bool{})
+	return &atlas
+}
+
+func (a *Atlas) appendTerraformModules(fileBytes []byte, ignoredPatterns []*regexp.Regexp, reported map[string]bool) {
+
+	for _, module := range parseTerraformBlocks(string(fileBytes), "module") {
+		source, version := module.Attributes["source"], module.Attributes["version"]
+		key := source + "@" + version
+		if source == "" || reported[key] || matchRegExpPatterns(ignoredPatterns, source) {
+			continue
+		}
+		reported[key] = true
+
+		address, ok := terraformModuleAddress(source)
+		switch {
+		case !ok:
+			a.appendDependency(&Dependency{Name: module.Label, LocalPath: source})
+		case version == "":
+			a.appendDependency(&Dependency{Name: address, VersionCurrentLiteral: "latest", Unpinned: true})
+		default:
+			a.appendDependency(NewTerraformConstraintDependency(address, version))
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package telescope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerraformConstraintAdmits(t *testing.T) {
+
+	params := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{constraint: "1.2.3", version: "1.2.3", expected: true},
+		{constraint: "= 1.2.3", version: "1.2.4", expected: false},
+		{constraint: "!= 1.2.3", version: "1.2.4", expected: true},
+		{constraint: ">= 1.2.0, < 2.0.0", version: "1.9.0", expected: true},
+		{constraint: ">= 1.2.0, < 2.0.0", version: "2.0.0", expected: false},
+		{constraint: "~> 1", version: "2.0.0", expected: true},
+		{constraint: "~> 1", version: "0.9.0", expected: false},
+		{constraint: "~> 1.2", version: "1.9.0", expected: true},
+		{constraint: "~> 1.2", version: "2.0.0", expected: false},
+		{constraint: "~> 1.2.3", version: "1.2.9", expected: true},
+		{constraint: "~> 1.2.3", version: "1.3.0", expected: false},
+		{constraint: "~> 1.2.3", version: "1.2.2", expected: false},
+		{constraint: ">= 1.0.0", version: "2.0.0-beta1", expected: false},
+		{constraint: "2.0.0-beta1", version: "2.0.0-beta1", expected: true},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.constraint+" "+param.version,
+			func(t *testing.T) {
+				t.Parallel()
+				constraint, err := NewTerraformConstraint(param.constraint)
+				assert.Nil(t, err)
+				version, err := NewSematicVersion(param.version, true)
+				assert.Nil(t, err)
+				assert.Equal(t, constraint.Admits(version), param.expected)
+			},
+		)
+	}
+
+	_, err := NewTerraformConstraint("^1.2")
+	assert.NotNil(t, err)
+}
+
+func TestTerraformModuleAddress(t *testing.T) {
+
+	params := []struct {
+		source   string
+		expected string
+		ok       bool
+	}{
+		{source: "terraform-aws-modules/vpc/aws", expected: "terraform-aws-modules/vpc/aws", ok: true},
+		{source: "app.terraform.io/acme/vpc/aws//modules/nat", expected: "app.terraform.io/acme/vpc/aws", ok: true},
+		{source: "./modules/network", ok: false},
+		{source: "github.com/acme/terraform-vpc", ok: false},
+		{source: "git::https://example.com/vpc.git?ref=v1.2.0", ok: false},
+	}
+	for _, param := range params {
+		param := param
+
+		t.Run(
+			param.source,
+			func(t *testing.T) {
+				t.Parallel()
+				address, ok := terraformModuleAddress(param.source)
+				assert.Equal(t, address, param.expected)
+				assert.Equal(t, ok, param.ok)
+			},
+		)
+	}
+}
+
+func TestParseTerraformBlocks(t *testing.T) {
+
+	blocks := parseTerraformBlocks(
+		`# This file is maintained automatically by "terraform init".
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+  ]
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0" // major version
+
+  tags = {
+    Name = "${var.name}-vpc"
+  }
+}
+`,
+		"provider",
+	)
+
+	assert.Equal(
+		t,
+		blocks,
+		[]TerraformBlock{
+			{
+				Label:      "registry.terraform.io/hashicorp/aws",
+				Attributes: map[string]string{"version": "5.31.0", "constraints": "~> 5.0"},
+			},
+		},
+	)
+}
+
+func TestQueryVersionsTerraform(t *testing.T) {
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/.well-known/terraform.json":
+				w.Write([]byte(`{"modules.v1": "/v1/modules/", "providers.v1": "/api/providers/"}`))
+			case "/api/providers/hashicorp/aws/versions":
+				w.Write([]byte(`{"versions": [{"version": "4.67.0"}, {"version": "5.31.0"}, {"version": "5.32.0-beta1"}]}`))
+			case "/v1/modules/terraform-aws-modules/vpc/aws/versions":
+				w.Write([]byte(`{"modules": [{"versions": [{"version": "4.0.2"}, {"version": "5.4.0"}, {"version": "5.1.2"}]}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer server.Close()
+
+	registry := TerraformRegistry
+	TerraformRegistry = server.URL
+	defer func() { TerraformRegistry = registry }()
+
+	provider := NewTerraformDependency("registry.terraform.io/hashicorp/aws", "4.67.0").(*Dependency)
+	provider.queryVersionsTerraform(ReleasePolicy{})
+	assert.Equal(t, provider.VersionLatest.String(), "5.31.0")
+	assert.Equal(t, provider.GetOutdatedScope(), MAJOR)
+
+	module := NewTerraformConstraintDependency("terraform-aws-modules/vpc/aws", "~> 4.0").(*Dependency)
+	module.queryVersionsTerraform(ReleasePolicy{})
+	assert.Equal(t, module.VersionLatest.String(), "5.4.0")
+	assert.Equal(t, module.VersionConstraintLatest.String(), "4.0.2")
+	assert.Equal(t, module.GetOutdatedScope(), CONSTRAINT_OUTDATED)
+
+	exact := NewTerraformConstraintDependency("terraform-aws-modules/vpc/aws", "= 5.1.2").(*Dependency)
+	exact.queryVersionsTerraform(ReleasePolicy{})
+	assert.Nil(t, exact.Constraint)
+	assert.Equal(t, exact.GetOutdatedScope(), MINOR)
+
+	cooled := NewTerraformDependency("registry.terraform.io/hashicorp/aws", "4.67.0").(*Dependency)
+	cooled.queryVersionsTerraform(ReleasePolicy{MinAge: 24 * time.Hour})
+	assert.Equal(t, cooled.VersionLatest.String(), "5.31.0")
+}
+
+func TestBuildAtlasTerraformLock(t *testing.T) {
+
+	project := writeFiles(t, map[string]string{
+		".terraform.lock.hcl": `provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+}
+`,
+		"main.tf": `module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+
+module "network" {
+  source = "./modules/network"
+}
+`,
+		"eks.tf": `module "eks" {
+  source = "terraform-aws-modules/eks/aws"
+}
+
+module "nat" {
+  source  = "app.terraform.io/acme/nat/aws//modules/gateway"
+  version = "1.4.0"
+}
+`,
+	})
+	lockPath := filepath.Join(project, ".terraform.lock.hcl")
+
+	atlas := buildAtlasTerraformLock(
+		lockPath,
+		parseDependenciesFile(lockPath),
+		[]*regexp.Regexp{regexp.MustCompile("random")},
+		map[OutdatedScope][]criticalRule{},
+	).(*Atlas)
+
+	assert.Equal(t, atlas.language, TERRAFORM)
+	assert.Equal(t, len(atlas.dependencies), 5)
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).Name, "registry.terraform.io/hashicorp/aws")
+	assert.Equal(t, atlas.dependencies[0].(*Dependency).VersionCurrent.String(), "5.31.0")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).Name, "terraform-aws-modules/eks/aws")
+	assert.Equal(t, atlas.dependencies[1].(*Dependency).GetOutdatedScope(), UNPINNED)
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).Name, "app.terraform.io/acme/nat/aws")
+	assert.Equal(t, atlas.dependencies[2].(*Dependency).VersionCurrent.String(), "1.4.0")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).Name, "terraform-aws-modules/vpc/aws")
+	assert.Equal(t, atlas.dependencies[3].(*Dependency).Constraint.String(), "~> 5.0")
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).LocalPath, "./modules/network")
+	assert.Equal(t, atlas.dependencies[4].(*Dependency).GetOutdatedScope(), LOCAL)
+}
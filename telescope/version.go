@@ -0,0 +1,45 @@
+package telescope
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver"
+)
+
+type IVersion interface {
+	String() string
+	Original() string
+	Major() int64
+	Minor() int64
+	Patch() int64
+	Prerelease() string
+}
+
+type IScopedVersion interface {
+	IVersion
+	Compare(other IVersion) int
+	OutdatedScope(latest IVersion) OutdatedScope
+}
+
+type IConstraint interface {
+	String() string
+	Admits(version IVersion) bool
+}
+
+func compareVersions(a, b IVersion) int {
+
+	if version, ok := a.(IScopedVersion); ok {
+		return version.Compare(b)
+	}
+	return a.(*semver.Version).Compare(b.(*semver.Version))
+}
+
+func sortVersions(versions []IVersion) {
+
+	sort.SliceStable(
+		versions,
+		func(i, j int) bool {
+			return compareVersions(versions[i], versions[j]) < 0
+		},
+	)
+}